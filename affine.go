@@ -0,0 +1,230 @@
+package bls12381
+
+import "errors"
+
+// PointG1 and PointG2 hold points in Jacobian coordinates where affine form is the case of z equal to one.
+// G1Affine and G2Affine are dedicated types for points in affine coordinates and G1Jac and G2Jac name
+// Jacobian points explicitly. Conversions and methods here never modify their inputs,
+// so affine points can be shared between goroutines as long as they are not written.
+
+// G1Jac is a G1 point in Jacobian coordinates.
+type G1Jac = PointG1
+
+// G2Jac is a G2 point in Jacobian coordinates.
+type G2Jac = PointG2
+
+// G1Affine is a G1 point in affine coordinates.
+// Point at infinity is represented as (0, 0) which is not on the curve.
+type G1Affine [2]fe
+
+// Set copies values of one point to another.
+func (p *G1Affine) Set(p2 *G1Affine) *G1Affine {
+	p[0].set(&p2[0])
+	p[1].set(&p2[1])
+	return p
+}
+
+// Zero sets the point to point at infinity.
+func (p *G1Affine) Zero() *G1Affine {
+	p[0].zero()
+	p[1].zero()
+	return p
+}
+
+// IsZero checks if the point is point at infinity.
+func (p *G1Affine) IsZero() bool {
+	return p[0].isZero() && p[1].isZero()
+}
+
+// ToAffine converts a G1 point into affine coordinates and assigns the result to point at first argument.
+func (g *G1) ToAffine(r *G1Affine, p *G1Jac) *G1Affine {
+	if g.IsZero(p) {
+		return r.Zero()
+	}
+	if g.IsAffine(p) {
+		r[0].set(&p[0])
+		r[1].set(&p[1])
+		return r
+	}
+	t := g.t
+	inverse(t[0], &p[2])
+	square(t[1], t[0])
+	mul(&r[0], &p[0], t[1])
+	mul(t[0], t[0], t[1])
+	mul(&r[1], &p[1], t[0])
+	return r
+}
+
+// FromAffine converts a G1 point in affine coordinates into Jacobian coordinates
+// and assigns the result to point at first argument.
+func (g *G1) FromAffine(r *G1Jac, p *G1Affine) *G1Jac {
+	if p.IsZero() {
+		return r.Zero()
+	}
+	r[0].set(&p[0])
+	r[1].set(&p[1])
+	r[2].one()
+	return r
+}
+
+// BatchToAffine converts G1 points into affine coordinates sharing a single inversion.
+func (g *G1) BatchToAffine(points []*G1Jac) []G1Affine {
+	out := make([]G1Affine, len(points))
+	inverses := make([]fe, len(points))
+	for i, p := range points {
+		inverses[i].set(&p[2])
+	}
+	inverseBatch(inverses)
+	t := g.t
+	for i, p := range points {
+		if g.IsZero(p) {
+			continue
+		}
+		square(t[1], &inverses[i])
+		mul(&out[i][0], &p[0], t[1])
+		mul(t[0], &inverses[i], t[1])
+		mul(&out[i][1], &p[1], t[0])
+	}
+	return out
+}
+
+// AddAffine adds a G1 point and a G1 point in affine coordinates and assigns the result to point at first argument.
+func (g *G1) AddAffine(r, p1 *G1Jac, p2 *G1Affine) *G1Jac {
+	if p2.IsZero() {
+		return r.Set(p1)
+	}
+	return g.AddMixed(r, p1, g.FromAffine(g.New(), p2))
+}
+
+// MultiExpAffine calculates multi exponentiation as MultiExp does for points in affine coordinates
+// with given number of concurrent workers. If number of workers is not positive GOMAXPROCS is used.
+func (g *G1) MultiExpAffine(r *G1Jac, points []*G1Affine, scalars []*Fr, workers int) (*G1Jac, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("point and scalar vectors should be in same length")
+	}
+	jac := make([]PointG1, len(points))
+	ptrs := make([]*PointG1, len(points))
+	for i, p := range points {
+		ptrs[i] = g.FromAffine(&jac[i], p)
+	}
+	return g.multiExp(r, ptrs, scalars, workers, false), nil
+}
+
+// affineCopies returns copies of G1 points in affine form.
+func (g *G1) affineCopies(points []*PointG1) []*PointG1 {
+	copies := make([]PointG1, len(points))
+	ptrs := make([]*PointG1, len(points))
+	for i, p := range points {
+		ptrs[i] = copies[i].Set(p)
+	}
+	g.AffineBatch(ptrs)
+	return ptrs
+}
+
+// G2Affine is a G2 point in affine coordinates.
+// Point at infinity is represented as (0, 0) which is not on the curve.
+type G2Affine [2]fe2
+
+// Set copies values of one point to another.
+func (p *G2Affine) Set(p2 *G2Affine) *G2Affine {
+	p[0].set(&p2[0])
+	p[1].set(&p2[1])
+	return p
+}
+
+// Zero sets the point to point at infinity.
+func (p *G2Affine) Zero() *G2Affine {
+	p[0].zero()
+	p[1].zero()
+	return p
+}
+
+// IsZero checks if the point is point at infinity.
+func (p *G2Affine) IsZero() bool {
+	return p[0].isZero() && p[1].isZero()
+}
+
+// ToAffine converts a G2 point into affine coordinates and assigns the result to point at first argument.
+func (g *G2) ToAffine(r *G2Affine, p *G2Jac) *G2Affine {
+	if g.IsZero(p) {
+		return r.Zero()
+	}
+	if g.IsAffine(p) {
+		r[0].set(&p[0])
+		r[1].set(&p[1])
+		return r
+	}
+	t := g.t
+	g.f.inverse(t[0], &p[2])
+	g.f.square(t[1], t[0])
+	g.f.mul(&r[0], &p[0], t[1])
+	g.f.mul(t[0], t[0], t[1])
+	g.f.mul(&r[1], &p[1], t[0])
+	return r
+}
+
+// FromAffine converts a G2 point in affine coordinates into Jacobian coordinates
+// and assigns the result to point at first argument.
+func (g *G2) FromAffine(r *G2Jac, p *G2Affine) *G2Jac {
+	if p.IsZero() {
+		return r.Zero()
+	}
+	r[0].set(&p[0])
+	r[1].set(&p[1])
+	r[2].one()
+	return r
+}
+
+// BatchToAffine converts G2 points into affine coordinates sharing a single inversion.
+func (g *G2) BatchToAffine(points []*G2Jac) []G2Affine {
+	out := make([]G2Affine, len(points))
+	inverses := make([]fe2, len(points))
+	for i, p := range points {
+		inverses[i].set(&p[2])
+	}
+	g.f.inverseBatch(inverses)
+	t := g.t
+	for i, p := range points {
+		if g.IsZero(p) {
+			continue
+		}
+		g.f.square(t[1], &inverses[i])
+		g.f.mul(&out[i][0], &p[0], t[1])
+		g.f.mul(t[0], &inverses[i], t[1])
+		g.f.mul(&out[i][1], &p[1], t[0])
+	}
+	return out
+}
+
+// AddAffine adds a G2 point and a G2 point in affine coordinates and assigns the result to point at first argument.
+func (g *G2) AddAffine(r, p1 *G2Jac, p2 *G2Affine) *G2Jac {
+	if p2.IsZero() {
+		return r.Set(p1)
+	}
+	return g.AddMixed(r, p1, g.FromAffine(g.New(), p2))
+}
+
+// MultiExpAffine calculates multi exponentiation as MultiExp does for points in affine coordinates
+// with given number of concurrent workers. If number of workers is not positive GOMAXPROCS is used.
+func (g *G2) MultiExpAffine(r *G2Jac, points []*G2Affine, scalars []*Fr, workers int) (*G2Jac, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("point and scalar vectors should be in same length")
+	}
+	jac := make([]PointG2, len(points))
+	ptrs := make([]*PointG2, len(points))
+	for i, p := range points {
+		ptrs[i] = g.FromAffine(&jac[i], p)
+	}
+	return g.multiExp(r, ptrs, scalars, workers, false), nil
+}
+
+// affineCopies returns copies of G2 points in affine form.
+func (g *G2) affineCopies(points []*PointG2) []*PointG2 {
+	copies := make([]PointG2, len(points))
+	ptrs := make([]*PointG2, len(points))
+	for i, p := range points {
+		ptrs[i] = copies[i].Set(p)
+	}
+	g.AffineBatch(ptrs)
+	return ptrs
+}
@@ -0,0 +1,145 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestG1Affine(t *testing.T) {
+	g := NewG1()
+	n := 10
+	points := make([]*G1Jac, n)
+	for i := range points {
+		points[i] = g.rand()
+	}
+	points[3] = g.Zero()
+	points[5] = g.Affine(g.rand())
+	batch := g.BatchToAffine(points)
+	for i, p := range points {
+		r, q := new(G1Affine), g.New()
+		g.ToAffine(r, p)
+		if *r != batch[i] {
+			t.Fatal("batch conversion failed")
+		}
+		if !g.Equal(p, g.FromAffine(q, r)) {
+			t.Fatal("conversion failed")
+		}
+		if g.IsZero(p) != r.IsZero() {
+			t.Fatal("bad point at infinity")
+		}
+		expected, result := g.New(), g.New()
+		g.Add(expected, points[0], p)
+		g.AddAffine(result, points[0], r)
+		if !g.Equal(expected, result) {
+			t.Fatal("addition with affine point failed")
+		}
+	}
+}
+
+func TestG2Affine(t *testing.T) {
+	g := NewG2()
+	n := 10
+	points := make([]*G2Jac, n)
+	for i := range points {
+		points[i] = g.rand()
+	}
+	points[3] = g.Zero()
+	points[5] = g.Affine(g.rand())
+	batch := g.BatchToAffine(points)
+	for i, p := range points {
+		r, q := new(G2Affine), g.New()
+		g.ToAffine(r, p)
+		if *r != batch[i] {
+			t.Fatal("batch conversion failed")
+		}
+		if !g.Equal(p, g.FromAffine(q, r)) {
+			t.Fatal("conversion failed")
+		}
+		if g.IsZero(p) != r.IsZero() {
+			t.Fatal("bad point at infinity")
+		}
+		expected, result := g.New(), g.New()
+		g.Add(expected, points[0], p)
+		g.AddAffine(result, points[0], r)
+		if !g.Equal(expected, result) {
+			t.Fatal("addition with affine point failed")
+		}
+	}
+}
+
+func TestInputsNotModified(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	e, _ := new(Fr).Rand(rand.Reader)
+	// points in Jacobian form which are not affine
+	p1, p2 := g1.Double(g1.New(), g1.randCorrect()), g2.Double(g2.New(), g2.randCorrect())
+	c1, c2 := *p1, *p2
+	if q, err := g1.FromUncompressed(g1.ToUncompressed(p1)); err != nil || !g1.Equal(q, p1) {
+		t.Fatal("encoding of point which is not affine failed")
+	}
+	g1.ToCompressed(p1)
+	g1.ToBytes(p1)
+	g1.MulScalar(g1.New(), p1, e)
+	_, _ = g1.MultiExp(g1.New(), []*PointG1{p1}, []*Fr{e})
+	if q, err := g2.FromUncompressed(g2.ToUncompressed(p2)); err != nil || !g2.Equal(q, p2) {
+		t.Fatal("encoding of point which is not affine failed")
+	}
+	g2.ToCompressed(p2)
+	g2.ToBytes(p2)
+	g2.MulScalar(g2.New(), p2, e)
+	_, _ = g2.MultiExp(g2.New(), []*PointG2{p2}, []*Fr{e})
+	engine := NewEngine()
+	engine.AddPair(p1, p2)
+	if *p1 != c1 || *p2 != c2 {
+		t.Fatal("input is modified")
+	}
+	// modifying points after they are added does not change the pairing
+	g1.Double(p1, p1)
+	result := engine.Result()
+	expected := NewEngine().AddPair(&c1, &c2).Result()
+	if !result.Equal(expected) {
+		t.Fatal("pairs must be copied")
+	}
+}
+
+func TestAffineAPI(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	n := 20
+	points1, points2 := make([]*G1Jac, n), make([]*G2Jac, n)
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		scalars[i], _ = new(Fr).Rand(rand.Reader)
+		points1[i], points2[i] = g1.rand(), g2.rand()
+	}
+	points1[2], points2[2] = g1.Zero(), g2.Zero()
+	affine1, affine2 := g1.BatchToAffine(points1), g2.BatchToAffine(points2)
+	ptrs1, ptrs2 := make([]*G1Affine, n), make([]*G2Affine, n)
+	for i := 0; i < n; i++ {
+		ptrs1[i], ptrs2[i] = &affine1[i], &affine2[i]
+	}
+	expected1, result1 := g1.New(), g1.New()
+	_, _ = g1.MultiExp(expected1, points1, scalars)
+	_, _ = g1.MultiExpAffine(result1, ptrs1, scalars, 0)
+	if !g1.Equal(expected1, result1) {
+		t.Fatal("multi exponentiation of affine points failed")
+	}
+	expected2, result2 := g2.New(), g2.New()
+	_, _ = g2.MultiExp(expected2, points2, scalars)
+	_, _ = g2.MultiExpAffine(result2, ptrs2, scalars, 0)
+	if !g2.Equal(expected2, result2) {
+		t.Fatal("multi exponentiation of affine points failed")
+	}
+	if _, err := g1.MultiExpAffine(result1, ptrs1, scalars[1:], 1); err == nil {
+		t.Fatal("length mismatch must be rejected")
+	}
+
+	e := NewEngine()
+	a1, a2 := new(G1Affine), new(G2Affine)
+	g1.ToAffine(a1, g1.MulScalar(g1.New(), g1.One(), scalars[0]))
+	g2.ToAffine(a2, g2.One())
+	e.AddPairAffine(a1, a2)
+	e.AddPairInv(g1.One(), g2.MulScalar(g2.New(), g2.One(), scalars[0]))
+	e.AddPairAffine(new(G1Affine), a2)
+	if !e.Check() {
+		t.Fatal("pairing of affine points failed")
+	}
+}
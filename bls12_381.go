@@ -52,6 +52,18 @@ var pPlus1Over4 = bigFromHex("0x680447a8e5ff9a692c6e9ed90d2eb35d91dd2e13ce144afd
 // pMinus1Over2 = (p - 1) / 2
 var pMinus1Over2 = bigFromHex("0xd0088f51cbff34d258dd3db21a5d66bb23ba5c279c2895fb39869507b587b120f55ffff58a9ffffdcff7fffffffd555")
 
+// pBig = p
+var pBig = bigFromHex("0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab")
+
+// pSquare = p ^ 2
+var pSquare = bigFromHex("0x2a437a4b8c35fc74bd278eaa22f25e9e2dc90e50e7046b466e59e49349e8bd050a62cfd16ddca6ef53149330978ef011d68619c86185c7b292e85a87091a04966bf91ed3e71b743162c338362113cfd7ced6b1d76382eab26aa00001c718e39")
+
+// pSquareMinus1Over2 = (p ^ 2 - 1) / 2
+var pSquareMinus1Over2 = bigFromHex("0x1521bd25c61afe3a5e93c75511792f4f16e48728738235a3372cf249a4f45e82853167e8b6ee5377a98a49984bc77808eb430ce430c2e3d949742d43848d024b35fc8f69f38dba18b1619c1b1089e7ebe76b58ebb1c1755935500000e38c71c")
+
+// pMinus2 = p - 2
+var pMinus2 = bigFromHex("0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaa9")
+
 // nonResidue1 = -1
 var nonResidue1 = &fe{0x43f5fffffffcaaae, 0x32b7fff2ed47fffd, 0x07e83a49a2e99d69, 0xeca8f3318332bb7a, 0xef148d1ea0f4c069, 0x040ab3263eff0206}
 
@@ -106,6 +118,12 @@ var cofactorEFFG1 = bigFromHex("0xd201000000010001")
 // Efficient G2 cofactor
 var cofactorEFFG2 = bigFromHex("0x0bc69f08f2ee75b3584c6a0ea91b352888e2a8e9145ad7689986ff031508ffe1329c2f178731db956d82bf015d1212b02ec0ec69d7477c1ae954cbc06689f6a359894c0adebbf6b4e8020005aaa95551")
 
+// Inverse of efficient G1 cofactor modulo group order
+var cofactorEFFG1Inv = bigFromHex("0x73eda753299d7d47a5e80b39939ed3351400480189fd0000ffff000000000001")
+
+// Inverse of efficient G2 cofactor modulo group order
+var cofactorEFFG2Inv = bigFromHex("0x2a45af93b77be6f6fe0d648e2d8073c16be35c8303cc1c1016b2602c95ec5209")
+
 // G1 generator
 var g1One = PointG1{
 	fe{0x5cb38790fd530c16, 0x7817fc679976fff5, 0x154f95c7143ba1c1, 0xf0ae6acdf3d0e747, 0xedce6ecc21dbf440, 0x120177419e0bfb75},
@@ -11,11 +11,14 @@ import (
 )
 
 var fuz int
+var dudect int
 
 func TestMain(m *testing.M) {
 	_fuz := flag.Int("fuzz", 10, "# of iterations")
+	_dudect := flag.Int("dudect", 0, "# of measurements for timing leakage tests")
 	flag.Parse()
 	fuz = *_fuz
+	dudect = *_dudect
 	os.Exit(m.Run())
 }
 
@@ -0,0 +1,482 @@
+package bls12381
+
+// Constant time helpers are used by hashing and mapping to curve in constant time mode.
+// Conditions are represented as uint64 values which are either 0 or 1 so that
+// selections are done with masks rather than branches. Exponentiations are applied
+// with public exponents so the sequence of operations does not depend on the secret input.
+
+// ctIsZero returns 1 if a is zero and 0 otherwise.
+func ctIsZero(a *fe) uint64 {
+	t := a[0] | a[1] | a[2] | a[3] | a[4] | a[5]
+	return ((t | -t) >> 63) ^ 1
+}
+
+// ctEqual returns 1 if a and b are equal and 0 otherwise.
+func ctEqual(a, b *fe) uint64 {
+	t := (a[0] ^ b[0]) | (a[1] ^ b[1]) | (a[2] ^ b[2]) | (a[3] ^ b[3]) | (a[4] ^ b[4]) | (a[5] ^ b[5])
+	return ((t | -t) >> 63) ^ 1
+}
+
+// ctParity returns the least significant bit of a in canonical form.
+func ctParity(a *fe) uint64 {
+	r := new(fe)
+	fromMont(r, a)
+	return r[0] & 1
+}
+
+// cmov assigns a to e if cond is 1 and leaves e unchanged if cond is 0.
+func (e *fe) cmov(a *fe, cond uint64) *fe {
+	mask := -cond
+	for i := 0; i < fpNumberOfLimbs; i++ {
+		e[i] ^= mask & (e[i] ^ a[i])
+	}
+	return e
+}
+
+func (e *fe2) cmov(a *fe2, cond uint64) *fe2 {
+	e[0].cmov(&a[0], cond)
+	e[1].cmov(&a[1], cond)
+	return e
+}
+
+// ctNeg is negation without branching on zero input.
+func ctNeg(c, a *fe) {
+	sub(c, zero, a)
+}
+
+// inverseCT calculates inverse of a with Fermat's little theorem.
+// Inverse of zero is zero.
+func inverseCT(c, a *fe) {
+	exp(c, a, pMinus2)
+}
+
+// sqrtCT assigns a candidate square root of a to c and returns 1 if a is a quadratic residue.
+func sqrtCT(c, a *fe) uint64 {
+	u, v := new(fe).set(a), new(fe)
+	// a ^ (p - 3) / 4
+	sqrtAddchain(c, u)
+	// a ^ (p + 1) / 4
+	mul(c, c, u)
+	square(v, c)
+	return ctEqual(u, v)
+}
+
+// isQuadraticNonResidueCT returns 1 if a is zero or a quadratic non residue.
+func isQuadraticNonResidueCT(a *fe) uint64 {
+	return ctIsZero(a) | (sqrtCT(new(fe), a) ^ 1)
+}
+
+func ctIsZero2(a *fe2) uint64 {
+	return ctIsZero(&a[0]) & ctIsZero(&a[1])
+}
+
+// ctParity2 is sgn0 of RFC 9380 for elements of quadratic extension.
+func ctParity2(a *fe2) uint64 {
+	return ctParity(&a[0]) | (ctIsZero(&a[0]) & ctParity(&a[1]))
+}
+
+func fp2NegCT(c, a *fe2) {
+	ctNeg(&c[0], &a[0])
+	ctNeg(&c[1], &a[1])
+}
+
+func (e *fp2) inverseCT(c, a *fe2) {
+	t := e.t
+	square(t[0], &a[0])
+	square(t[1], &a[1])
+	addAssign(t[0], t[1])
+	inverseCT(t[0], t[0])
+	mul(&c[0], &a[0], t[0])
+	mul(t[0], t[0], &a[1])
+	ctNeg(&c[1], t[0])
+}
+
+func (e *fp2) isQuadraticNonResidueCT(a *fe2) uint64 {
+	c0, c1 := new(fe), new(fe)
+	square(c0, &a[0])
+	square(c1, &a[1])
+	add(c1, c1, c0)
+	return isQuadraticNonResidueCT(c1)
+}
+
+// sqrtCT is constant time version of sqrtBLST.
+// It assigns a candidate square root of a to c and returns 1 if a is a quadratic residue.
+func (e *fp2) sqrtCT(c, a *fe2) uint64 {
+	aa, bb := new(fe), new(fe)
+	ret := new(fe2)
+	square(aa, &a[0])
+	square(bb, &a[1])
+	add(aa, aa, bb)
+	sqrtCT(aa, aa)
+	sub(bb, &a[0], aa)
+	add(aa, &a[0], aa)
+	aa.cmov(bb, ctIsZero(aa))
+	mul(aa, aa, twoInv)
+	sqrtAddchain(&ret[0], aa)
+	mul(&ret[1], &a[1], twoInv)
+	mul(&ret[1], &ret[1], &ret[0])
+	mul(&ret[0], &ret[0], aa)
+
+	// align the candidate with one of the four roots of unity
+	t0, t1 := new(fe2), new(fe2)
+	coeff := e.one()
+	e.square(t0, ret)
+
+	fp2Sub(t1, t0, a)
+	isSqrt := ctIsZero2(t1)
+
+	fp2Add(t1, t0, a)
+	flag := ctIsZero2(t1)
+	coeff.cmov(sqrtMinus1, flag)
+	isSqrt |= flag
+
+	sub(&t1[0], &t0[0], &a[1])
+	add(&t1[1], &t0[1], &a[0])
+	flag = ctIsZero2(t1)
+	coeff.cmov(sqrtSqrtMinus1, flag)
+	isSqrt |= flag
+
+	add(&t1[0], &t0[0], &a[1])
+	sub(&t1[1], &t0[1], &a[0])
+	flag = ctIsZero2(t1)
+	coeff.cmov(sqrtMinusSqrtMinus1, flag)
+	isSqrt |= flag
+
+	e.mul(c, coeff, ret)
+	return isSqrt
+}
+
+// ctEqualUint64 returns 1 if a and b are equal and 0 otherwise.
+func ctEqualUint64(a, b uint64) uint64 {
+	t := a ^ b
+	return ((t | -t) >> 63) ^ 1
+}
+
+// Constant time scalar multiplication uses signed digits of ctMulWindowSize bits
+// and a table of ctMulRowSize multiples of the point.
+const (
+	ctMulWindowSize = 4
+	ctMulWindows    = 255/ctMulWindowSize + 1
+	ctMulRowSize    = 1 << (ctMulWindowSize - 1)
+)
+
+// ctSignedDigits recodes scalar into signed digits of c bits without branching.
+// Absolute values of digits are in [0, 2^(c-1)] and signs are one for negative digits.
+// Number of windows must satisfy c * windows > 255.
+func ctSignedDigits(s *Fr, c uint, windows int) (digits, signs []uint64) {
+	digits, signs = make([]uint64, windows), make([]uint64, windows)
+	mask := uint64(1)<<c - 1
+	half := uint64(1) << (c - 1)
+	carry := uint64(0)
+	for j := 0; j < windows; j++ {
+		d := s.sliceUint64(int(c)*j)&mask + carry
+		// d > half
+		carry = (half - d) >> 63
+		digits[j] = d ^ ((d ^ (mask + 1 - d)) & -carry)
+		signs[j] = carry
+	}
+	return
+}
+
+// cmov assigns q to p if cond is 1 and leaves p unchanged if cond is 0.
+func (p *PointG1) cmov(q *PointG1, cond uint64) *PointG1 {
+	p[0].cmov(&q[0], cond)
+	p[1].cmov(&q[1], cond)
+	p[2].cmov(&q[2], cond)
+	return p
+}
+
+func (p *PointG2) cmov(q *PointG2, cond uint64) *PointG2 {
+	p[0].cmov(&q[0], cond)
+	p[1].cmov(&q[1], cond)
+	p[2].cmov(&q[2], cond)
+	return p
+}
+
+// doubleCT is Double without branching on zero input.
+// Doubling of zero gives a point with zero z coordinate.
+func (g *G1) doubleCT(r, p *PointG1) *PointG1 {
+	t := g.t
+	square(t[0], &p[0])
+	square(t[1], &p[1])
+	square(t[2], t[1])
+	add(t[1], &p[0], t[1])
+	square(t[1], t[1])
+	subAssign(t[1], t[0])
+	subAssign(t[1], t[2])
+	doubleAssign(t[1])
+	double(t[3], t[0])
+	addAssign(t[0], t[3])
+	square(t[4], t[0])
+	double(t[3], t[1])
+	sub(&r[0], t[4], t[3])
+	subAssign(t[1], &r[0])
+	doubleAssign(t[2])
+	doubleAssign(t[2])
+	doubleAssign(t[2])
+	mul(t[0], t[0], t[1])
+	sub(t[1], t[0], t[2])
+	mul(t[0], &p[1], &p[2])
+	r[1].set(t[1])
+	double(&r[2], t[0])
+	return r
+}
+
+// addCT is Add without branching. Both the sum and the doubling are calculated and the result
+// is selected with conditional moves in case one of the points is zero or points are equal.
+// If p1 is equal to -p2 the sum formula gives zero.
+func (g *G1) addCT(r, p1, p2 *PointG1) *PointG1 {
+	sum, dbl := new(PointG1), new(PointG1)
+	g.doubleCT(dbl, p1)
+	t := g.t
+	square(t[7], &p1[2])
+	mul(t[1], &p2[0], t[7]) // u2 = x2 * z1z1
+	mul(t[2], &p1[2], t[7])
+	mul(t[0], &p2[1], t[2]) // s2 = y2 * z1z1 * z1
+	square(t[8], &p2[2])
+	mul(t[3], &p1[0], t[8]) // u1 = x1 * z2z2
+	mul(t[4], &p2[2], t[8])
+	mul(t[2], &p1[1], t[4]) // s1 = y1 * z2z2 * z2
+	subAssign(t[1], t[3])   // h = u2 - u1
+	subAssign(t[0], t[2])
+	doubleAssign(t[0]) // r = 2*(s2 - s1)
+	equal := ctIsZero(t[1]) & ctIsZero(t[0])
+	double(t[4], t[1])
+	square(t[4], t[4])
+	mul(t[5], t[1], t[4])
+	square(t[6], t[0])
+	subAssign(t[6], t[5])
+	mul(t[3], t[3], t[4])
+	double(t[4], t[3])
+	sub(&sum[0], t[6], t[4])
+	sub(t[4], t[3], &sum[0])
+	mul(t[6], t[2], t[5])
+	doubleAssign(t[6])
+	mul(t[0], t[0], t[4])
+	sub(&sum[1], t[0], t[6])
+	add(t[0], &p1[2], &p2[2])
+	square(t[0], t[0])
+	subAssign(t[0], t[7])
+	subAssign(t[0], t[8])
+	mul(&sum[2], t[0], t[1])
+	sum.cmov(dbl, equal)
+	sum.cmov(p2, ctIsZero(&p1[2]))
+	sum.cmov(p1, ctIsZero(&p2[2]))
+	return r.Set(sum)
+}
+
+// addMixedCT is AddMixed without branching where p2 is expected to be in affine form and not zero.
+// Both the sum and the doubling are calculated and the result is selected with conditional moves
+// in case p1 is zero or p1 is equal to p2. If p1 is equal to -p2 the sum formula gives zero.
+func (g *G1) addMixedCT(r, p1, p2 *PointG1) *PointG1 {
+	sum, dbl := new(PointG1), new(PointG1)
+	g.doubleCT(dbl, p1)
+	t := g.t
+	square(t[7], &p1[2])
+	mul(t[1], &p2[0], t[7])
+	mul(t[2], &p1[2], t[7])
+	mul(t[0], &p2[1], t[2])
+	sub(t[1], t[1], &p1[0]) // h = u2 - x1
+	square(t[2], t[1])
+	double(t[4], t[2])
+	doubleAssign(t[4])
+	mul(t[5], t[1], t[4])
+	subAssign(t[0], &p1[1])
+	doubleAssign(t[0]) // r = 2*(s2 - y1)
+	equal := ctIsZero(t[1]) & ctIsZero(t[0])
+	square(t[6], t[0])
+	subAssign(t[6], t[5])
+	mul(t[3], &p1[0], t[4])
+	double(t[4], t[3])
+	sub(&sum[0], t[6], t[4])
+	sub(t[4], t[3], &sum[0])
+	mul(t[6], &p1[1], t[5])
+	doubleAssign(t[6])
+	mul(t[0], t[0], t[4])
+	sub(&sum[1], t[0], t[6])
+	add(t[0], &p1[2], t[1])
+	square(t[0], t[0])
+	subAssign(t[0], t[7])
+	sub(&sum[2], t[0], t[2])
+	sum.cmov(dbl, equal)
+	sum.cmov(p2, ctIsZero(&p1[2]))
+	return r.Set(sum)
+}
+
+// doubleCT is Double without branching on zero input.
+// Doubling of zero gives a point with zero z coordinate.
+func (g *G2) doubleCT(r, p *PointG2) *PointG2 {
+	t := g.t
+	g.f.square(t[0], &p[0])
+	g.f.square(t[1], &p[1])
+	g.f.square(t[2], t[1])
+	fp2AddAssign(t[1], &p[0])
+	g.f.squareAssign(t[1])
+	fp2SubAssign(t[1], t[0])
+	fp2SubAssign(t[1], t[2])
+	fp2DoubleAssign(t[1])
+	fp2Double(t[3], t[0])
+	fp2AddAssign(t[0], t[3])
+	g.f.square(t[4], t[0])
+	fp2Double(t[3], t[1])
+	fp2Sub(&r[0], t[4], t[3])
+	fp2SubAssign(t[1], &r[0])
+	fp2DoubleAssign(t[2])
+	fp2DoubleAssign(t[2])
+	fp2DoubleAssign(t[2])
+	g.f.mulAssign(t[0], t[1])
+	fp2Sub(t[1], t[0], t[2])
+	g.f.mul(t[0], &p[1], &p[2])
+	r[1].set(t[1])
+	fp2Double(&r[2], t[0])
+	return r
+}
+
+// addCT is Add without branching. Both the sum and the doubling are calculated and the result
+// is selected with conditional moves in case one of the points is zero or points are equal.
+// If p1 is equal to -p2 the sum formula gives zero.
+func (g *G2) addCT(r, p1, p2 *PointG2) *PointG2 {
+	sum, dbl := new(PointG2), new(PointG2)
+	g.doubleCT(dbl, p1)
+	t := g.t
+	g.f.square(t[7], &p1[2])
+	g.f.mul(t[1], &p2[0], t[7]) // u2 = x2 * z1z1
+	g.f.mul(t[2], &p1[2], t[7])
+	g.f.mul(t[0], &p2[1], t[2]) // s2 = y2 * z1z1 * z1
+	g.f.square(t[8], &p2[2])
+	g.f.mul(t[3], &p1[0], t[8]) // u1 = x1 * z2z2
+	g.f.mul(t[4], &p2[2], t[8])
+	g.f.mul(t[2], &p1[1], t[4]) // s1 = y1 * z2z2 * z2
+	fp2SubAssign(t[1], t[3])    // h = u2 - u1
+	fp2SubAssign(t[0], t[2])
+	fp2DoubleAssign(t[0]) // r = 2*(s2 - s1)
+	equal := ctIsZero2(t[1]) & ctIsZero2(t[0])
+	fp2Double(t[4], t[1])
+	g.f.squareAssign(t[4])
+	g.f.mul(t[5], t[1], t[4])
+	g.f.square(t[6], t[0])
+	fp2SubAssign(t[6], t[5])
+	g.f.mulAssign(t[3], t[4])
+	fp2Double(t[4], t[3])
+	fp2Sub(&sum[0], t[6], t[4])
+	fp2Sub(t[4], t[3], &sum[0])
+	g.f.mul(t[6], t[2], t[5])
+	fp2DoubleAssign(t[6])
+	g.f.mulAssign(t[0], t[4])
+	fp2Sub(&sum[1], t[0], t[6])
+	fp2Add(t[0], &p1[2], &p2[2])
+	g.f.squareAssign(t[0])
+	fp2SubAssign(t[0], t[7])
+	fp2SubAssign(t[0], t[8])
+	g.f.mul(&sum[2], t[0], t[1])
+	sum.cmov(dbl, equal)
+	sum.cmov(p2, ctIsZero2(&p1[2]))
+	sum.cmov(p1, ctIsZero2(&p2[2]))
+	return r.Set(sum)
+}
+
+// addMixedCT is AddMixed without branching where p2 is expected to be in affine form and not zero.
+// Both the sum and the doubling are calculated and the result is selected with conditional moves
+// in case p1 is zero or p1 is equal to p2. If p1 is equal to -p2 the sum formula gives zero.
+func (g *G2) addMixedCT(r, p1, p2 *PointG2) *PointG2 {
+	sum, dbl := new(PointG2), new(PointG2)
+	g.doubleCT(dbl, p1)
+	t := g.t
+	g.f.square(t[7], &p1[2])
+	g.f.mul(t[1], &p2[0], t[7])
+	g.f.mul(t[2], &p1[2], t[7])
+	g.f.mul(t[0], &p2[1], t[2])
+	fp2SubAssign(t[1], &p1[0]) // h = u2 - x1
+	g.f.square(t[2], t[1])
+	fp2Double(t[4], t[2])
+	fp2DoubleAssign(t[4])
+	g.f.mul(t[5], t[1], t[4])
+	fp2SubAssign(t[0], &p1[1])
+	fp2DoubleAssign(t[0]) // r = 2*(s2 - y1)
+	equal := ctIsZero2(t[1]) & ctIsZero2(t[0])
+	g.f.square(t[6], t[0])
+	fp2SubAssign(t[6], t[5])
+	g.f.mul(t[3], &p1[0], t[4])
+	fp2Double(t[4], t[3])
+	fp2Sub(&sum[0], t[6], t[4])
+	fp2Sub(t[4], t[3], &sum[0])
+	g.f.mul(t[6], &p1[1], t[5])
+	fp2DoubleAssign(t[6])
+	g.f.mulAssign(t[0], t[4])
+	fp2Sub(&sum[1], t[0], t[6])
+	fp2Add(t[0], &p1[2], t[1])
+	g.f.squareAssign(t[0])
+	fp2SubAssign(t[0], t[7])
+	fp2Sub(&sum[2], t[0], t[2])
+	sum.cmov(dbl, equal)
+	sum.cmov(p2, ctIsZero2(&p1[2]))
+	return r.Set(sum)
+}
+
+// clearCofactorCT is ClearCofactor with additions and doublings which do not branch.
+func (g *G1) clearCofactorCT(p *PointG1) *PointG1 {
+	chain := func(p0 *PointG1, n int, p1 *PointG1) {
+		for i := 0; i < n; i++ {
+			g.doubleCT(p0, p0)
+		}
+		g.addCT(p0, p0, p1)
+	}
+	t := g.New().Set(p)
+	chain(p, 1, t)
+	chain(p, 2, t)
+	chain(p, 3, t)
+	chain(p, 9, t)
+	chain(p, 32, t)
+	chain(p, 16, t)
+	return p
+}
+
+// subCT is Sub without branching.
+func (g *G2) subCT(r, p1, p2 *PointG2) *PointG2 {
+	q := new(PointG2).Set(p2)
+	fp2NegCT(&q[1], &p2[1])
+	return g.addCT(r, p1, q)
+}
+
+// psiCT is psi where conjugation does not branch on zero coordinates.
+func (g *G2) psiCT(p *PointG2) {
+	ctNeg(&p[0][1], &p[0][1])
+	ctNeg(&p[1][1], &p[1][1])
+	ctNeg(&p[2][1], &p[2][1])
+	g.f.mul(&p[0], &p[0], &psix)
+	g.f.mul(&p[1], &p[1], &psiy)
+}
+
+// mulXCT is mulX with additions and doublings which do not branch.
+func (g *G2) mulXCT(p *PointG2) {
+	chain := func(p0 *PointG2, n int, p1 *PointG2) {
+		g.addCT(p0, p0, p1)
+		for i := 0; i < n; i++ {
+			g.doubleCT(p0, p0)
+		}
+	}
+	t := g.New().Set(p)
+	g.doubleCT(p, t)
+	chain(p, 2, t)
+	chain(p, 3, t)
+	chain(p, 9, t)
+	chain(p, 32, t)
+	chain(p, 16, t)
+}
+
+// clearCofactorCT is ClearCofactor with point operations which do not branch.
+func (g *G2) clearCofactorCT(p *PointG2) *PointG2 {
+	t0, t1, t2, t3 := g.New().Set(p), g.New().Set(p), g.New().Set(p), g.New()
+	g.doubleCT(t0, t0)
+	g.psiCT(t0)
+	g.psiCT(t0)         // P2 = ψ^2(2P)
+	g.psiCT(t2)         // P1 = ψ(P)
+	g.mulXCT(t1)        // -xP0
+	g.subCT(t3, t1, t2) // -xP0 - P1
+	g.mulXCT(t3)        // (x^2)P0 + xP1
+	g.subCT(t1, t1, p)  // (-x-1)P0
+	g.addCT(t3, t3, t1) // (x^2-x-1)P0 + xP1
+	g.subCT(t3, t3, t2) // (x^2-x-1)P0 + (x-1)P1
+	g.addCT(t3, t3, t0) // (x^2-x-1)P0 + (x-1)P1 + P2
+	return p.Set(t3)
+}
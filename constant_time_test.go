@@ -0,0 +1,477 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestConstantTimeFieldOperations(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe).rand(rand.Reader)
+		b, _ := new(fe).rand(rand.Reader)
+		if ctIsZero(a) != 0 || ctIsZero(zero) != 1 {
+			t.Fatal("ct is zero")
+		}
+		if ctEqual(a, b) != 0 || ctEqual(a, new(fe).set(a)) != 1 {
+			t.Fatal("ct equal")
+		}
+		c := new(fe).set(a)
+		if !c.cmov(b, 0).equal(a) || !c.cmov(b, 1).equal(b) {
+			t.Fatal("cmov")
+		}
+		r0, r1 := new(fe), new(fe)
+		inverse(r0, a)
+		inverseCT(r1, a)
+		if !r0.equal(r1) {
+			t.Fatal("ct inversion")
+		}
+		ok0 := sqrt(r0, a)
+		ok1 := sqrtCT(r1, a)
+		if ok0 != (ok1 == 1) || (ok0 && !r0.equal(r1)) {
+			t.Fatal("ct square root")
+		}
+		if isQuadraticNonResidue(a) != (isQuadraticNonResidueCT(a) == 1) {
+			t.Fatal("ct quadratic residuosity")
+		}
+		ctNeg(r1, a)
+		neg(r0, a)
+		if !r0.equal(r1) {
+			t.Fatal("ct negation")
+		}
+	}
+	r := new(fe)
+	inverseCT(r, zero)
+	ctNeg(r, zero)
+	if !r.isZero() {
+		t.Fatal("ct negation of zero")
+	}
+	if isQuadraticNonResidueCT(zero) != 1 {
+		t.Fatal("ct quadratic residuosity of zero")
+	}
+	e := newFp2()
+	for i := 0; i < fuz; i++ {
+		a, _ := new(fe2).rand(rand.Reader)
+		r0, r1 := new(fe2), new(fe2)
+		e.inverse(r0, a)
+		e.inverseCT(r1, a)
+		if !r0.equal(r1) {
+			t.Fatal("ct inversion")
+		}
+		ok0 := e.sqrtBLST(r0, a)
+		ok1 := e.sqrtCT(r1, a)
+		if ok0 != (ok1 == 1) || !r0.equal(r1) {
+			t.Fatal("ct square root")
+		}
+		e.square(a, a)
+		if e.sqrtCT(r1, a) != 1 || !e.sqrtBLST(r0, a) || !r0.equal(r1) {
+			t.Fatal("ct square root of a square")
+		}
+		if e.isQuadraticNonResidue(a) != (e.isQuadraticNonResidueCT(a) == 1) {
+			t.Fatal("ct quadratic residuosity")
+		}
+		if a.sign() != (ctParity2(a) == 0) {
+			t.Fatal("ct sign")
+		}
+		a[0].zero()
+		if a.sign() != (ctParity2(a) == 0) {
+			t.Fatal("ct sign")
+		}
+	}
+}
+
+func TestG1HashToCurveCT(t *testing.T) {
+	g := NewG1()
+	for _, name := range []string{
+		"BLS12381G1_XMD-SHA-256_SSWU_RO_",
+		"BLS12381G1_XMD-SHA-256_SSWU_NU_",
+	} {
+		suite := hashToCurveSuite{}
+		readHashToCurveVectors(t, name, &suite)
+		for i, v := range suite.Vectors {
+			hash := g.EncodeToCurveCT
+			if suite.RandomOracle {
+				hash = g.HashToCurveCT
+			}
+			p, err := hash([]byte(v.Msg), []byte(suite.DST))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := append(fromHexFe(v.P.X), fromHexFe(v.P.Y)...)
+			if !bytes.Equal(g.ToBytes(p), expected) {
+				t.Fatal("constant time hash to curve fails", name, i)
+			}
+		}
+	}
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe).rand(rand.Reader)
+		in := toBytes(u)
+		p0, err := g.MapToCurve(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p1, err := g.MapToCurveCT(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(p0, p1) {
+			t.Fatal("constant time map to curve fails")
+		}
+	}
+	// exceptional case of simplified swu where u = 0
+	p0, _ := g.MapToCurve(make([]byte, fpByteSize))
+	p1, _ := g.MapToCurveCT(make([]byte, fpByteSize))
+	if !g.Equal(p0, p1) {
+		t.Fatal("constant time map to curve fails at zero")
+	}
+}
+
+func TestG2HashToCurveCT(t *testing.T) {
+	g := NewG2()
+	for _, name := range []string{
+		"BLS12381G2_XMD-SHA-256_SSWU_RO_",
+		"BLS12381G2_XMD-SHA-256_SSWU_NU_",
+	} {
+		suite := hashToCurveSuite{}
+		readHashToCurveVectors(t, name, &suite)
+		for i, v := range suite.Vectors {
+			hash := g.EncodeToCurveCT
+			if suite.RandomOracle {
+				hash = g.HashToCurveCT
+			}
+			p, err := hash([]byte(v.Msg), []byte(suite.DST))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := append(fromHexFe(v.P.X), fromHexFe(v.P.Y)...)
+			if !bytes.Equal(g.ToBytes(p), expected) {
+				t.Fatal("constant time hash to curve fails", name, i)
+			}
+		}
+	}
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe2).rand(rand.Reader)
+		in := g.f.toBytes(u)
+		p0, err := g.MapToCurve(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p1, err := g.MapToCurveCT(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(p0, p1) {
+			t.Fatal("constant time map to curve fails")
+		}
+	}
+	p0, _ := g.MapToCurve(make([]byte, 2*fpByteSize))
+	p1, _ := g.MapToCurveCT(make([]byte, 2*fpByteSize))
+	if !g.Equal(p0, p1) {
+		t.Fatal("constant time map to curve fails at zero")
+	}
+}
+
+func TestHashToCurveCTRandom(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	domain := []byte("BLS12381_XMD:SHA-256_SSWU_CT_TEST_")
+	for i := 0; i < fuz; i++ {
+		msg := make([]byte, 32)
+		_, _ = rand.Read(msg)
+		for _, hash := range []struct {
+			ct, vt func(msg, domain []byte) (*PointG1, error)
+		}{
+			{g1.HashToCurveCT, g1.HashToCurve},
+			{g1.EncodeToCurveCT, g1.EncodeToCurve},
+		} {
+			p0, err0 := hash.vt(msg, domain)
+			p1, err1 := hash.ct(msg, domain)
+			if err0 != nil || err1 != nil {
+				t.Fatal(err0, err1)
+			}
+			if !g1.Equal(p0, p1) || !g1.IsAffine(p1) {
+				t.Fatal("constant time hash to curve does not match for G1")
+			}
+		}
+		for _, hash := range []struct {
+			ct, vt func(msg, domain []byte) (*PointG2, error)
+		}{
+			{g2.HashToCurveCT, g2.HashToCurve},
+			{g2.EncodeToCurveCT, g2.EncodeToCurve},
+		} {
+			p0, err0 := hash.vt(msg, domain)
+			p1, err1 := hash.ct(msg, domain)
+			if err0 != nil || err1 != nil {
+				t.Fatal(err0, err1)
+			}
+			if !g2.Equal(p0, p1) || !g2.IsAffine(p1) {
+				t.Fatal("constant time hash to curve does not match for G2")
+			}
+		}
+	}
+}
+
+func TestClearCofactorCT(t *testing.T) {
+	g1 := NewG1()
+	for i := 0; i < fuz; i++ {
+		p := g1.rand()
+		expected := g1.ClearCofactor(new(PointG1).Set(p))
+		r := g1.clearCofactorCT(new(PointG1).Set(p))
+		if !g1.Equal(expected, r) || !g1.InCorrectSubgroup(r) {
+			t.Fatal("constant time cofactor clearing failed")
+		}
+		if !g1.Equal(g1.affineCT(r), expected) || !g1.IsAffine(r) {
+			t.Fatal("constant time affine conversion failed")
+		}
+	}
+	if !g1.IsZero(g1.clearCofactorCT(g1.Zero())) || !g1.IsZero(g1.affineCT(g1.Zero())) {
+		t.Fatal("constant time operations on zero failed")
+	}
+	g2 := NewG2()
+	for i := 0; i < fuz; i++ {
+		p := g2.rand()
+		expected := g2.ClearCofactor(new(PointG2).Set(p))
+		r := g2.clearCofactorCT(new(PointG2).Set(p))
+		if !g2.Equal(expected, r) || !g2.InCorrectSubgroup(r) {
+			t.Fatal("constant time cofactor clearing failed")
+		}
+		if !g2.Equal(g2.affineCT(r), expected) || !g2.IsAffine(r) {
+			t.Fatal("constant time affine conversion failed")
+		}
+	}
+	if !g2.IsZero(g2.clearCofactorCT(g2.Zero())) || !g2.IsZero(g2.affineCT(g2.Zero())) {
+		t.Fatal("constant time operations on zero failed")
+	}
+}
+
+// Timing leakage tests follow dudect approach.
+// https://eprint.iacr.org/2016/1123
+// Inputs are drawn either from a fixed class or from a random class in random order and
+// execution times of the two classes are compared with Welch's t-test after cropping
+// long measurements at several percentiles. An absolute t value above 4.5 indicates leakage.
+// These tests are noisy and slow, so they only run when number of measurements is given
+// with -dudect flag, for example:
+//
+//	go test -run Dudect -dudect 200000
+const dudectThreshold = 4.5
+
+// welch accumulates running mean and variance of a measurement class.
+type welch struct {
+	n, mean, m2 float64
+}
+
+func (w *welch) push(x float64) {
+	w.n++
+	d := x - w.mean
+	w.mean += d / w.n
+	w.m2 += d * (x - w.mean)
+}
+
+func welchT(a, b *welch) float64 {
+	if a.n < 2 || b.n < 2 {
+		return 0
+	}
+	va, vb := a.m2/(a.n-1), b.m2/(b.n-1)
+	if va+vb == 0 {
+		return 0
+	}
+	return (a.mean - b.mean) / math.Sqrt(va/a.n+vb/b.n)
+}
+
+// dudectMeasure runs f over inputs of two classes and returns the largest absolute t statistic.
+// fixed and random return the inputs of the first and the second class respectively.
+func dudectMeasure(n int, fixed, random func() []byte, f func([]byte)) float64 {
+	classes := make([]byte, n)
+	_, _ = rand.Read(classes)
+	inputs := make([][]byte, n)
+	for i := range inputs {
+		classes[i] &= 1
+		if classes[i] == 0 {
+			inputs[i] = fixed()
+		} else {
+			inputs[i] = random()
+		}
+	}
+	// warm up
+	for i := 0; i < n/100+1; i++ {
+		f(inputs[i%n])
+	}
+	times := make([]float64, n)
+	for i := range inputs {
+		start := time.Now()
+		f(inputs[i])
+		times[i] = float64(time.Since(start))
+	}
+	sorted := append([]float64{}, times...)
+	sort.Float64s(sorted)
+	thresholds := []float64{math.Inf(1)}
+	for k := 0; k < 10; k++ {
+		p := 1 - math.Pow(0.5, 10*float64(k+1)/100)
+		thresholds = append(thresholds, sorted[int(p*float64(n-1))])
+	}
+	max := 0.0
+	for _, threshold := range thresholds {
+		var acc [2]welch
+		for i, x := range times {
+			if x <= threshold {
+				acc[classes[i]].push(x)
+			}
+		}
+		if t := math.Abs(welchT(&acc[0], &acc[1])); t > max {
+			max = t
+		}
+	}
+	return max
+}
+
+func dudectRun(t *testing.T, name string, fixed, random func() []byte, f func([]byte)) {
+	if dudect == 0 {
+		t.Skip("timing leakage tests run with -dudect flag")
+	}
+	tValue := dudectMeasure(dudect, fixed, random, f)
+	t.Logf("%s: max |t| = %.2f with %d measurements", name, tValue, dudect)
+	if tValue > dudectThreshold {
+		t.Errorf("%s: timing leakage detected, |t| = %.2f", name, tValue)
+	}
+}
+
+func TestDudectG1MapToCurveCT(t *testing.T) {
+	g := NewG1()
+	// zero is the exceptional input of simplified swu
+	fixed := func() []byte { return make([]byte, fpByteSize) }
+	random := func() []byte {
+		u, _ := new(fe).rand(rand.Reader)
+		return toBytes(u)
+	}
+	dudectRun(t, "G1 MapToCurveCT", fixed, random, func(in []byte) { _, _ = g.MapToCurveCT(in) })
+}
+
+func TestDudectG2MapToCurveCT(t *testing.T) {
+	g := NewG2()
+	fixed := func() []byte { return make([]byte, 2*fpByteSize) }
+	random := func() []byte {
+		u, _ := new(fe2).rand(rand.Reader)
+		return g.f.toBytes(u)
+	}
+	dudectRun(t, "G2 MapToCurveCT", fixed, random, func(in []byte) { _, _ = g.MapToCurveCT(in) })
+}
+
+func TestDudectG1HashToCurveCT(t *testing.T) {
+	g := NewG1()
+	domain := []byte("BLS12381G1_XMD:SHA-256_SSWU_RO_TEST")
+	fixed := func() []byte { return make([]byte, 32) }
+	random := func() []byte {
+		msg := make([]byte, 32)
+		_, _ = rand.Read(msg)
+		return msg
+	}
+	dudectRun(t, "G1 HashToCurveCT", fixed, random, func(msg []byte) { _, _ = g.HashToCurveCT(msg, domain) })
+}
+
+func TestDudectG2HashToCurveCT(t *testing.T) {
+	g := NewG2()
+	domain := []byte("BLS12381G2_XMD:SHA-256_SSWU_RO_TEST")
+	fixed := func() []byte { return make([]byte, 32) }
+	random := func() []byte {
+		msg := make([]byte, 32)
+		_, _ = rand.Read(msg)
+		return msg
+	}
+	dudectRun(t, "G2 HashToCurveCT", fixed, random, func(msg []byte) { _, _ = g.HashToCurveCT(msg, domain) })
+}
+
+func TestAddCT(t *testing.T) {
+	g1 := NewG1()
+	for i := 0; i < fuz; i++ {
+		p, q := g1.rand(), g1.rand()
+		for _, a := range []*PointG1{p, g1.Zero(), new(PointG1).Set(q), g1.Neg(g1.New(), q)} {
+			for _, b := range []*PointG1{q, g1.Zero()} {
+				expected, r := g1.New(), g1.New()
+				g1.Add(expected, a, b)
+				g1.addCT(r, a, b)
+				if !g1.Equal(expected, r) {
+					t.Fatal("constant time addition failed")
+				}
+			}
+		}
+	}
+	g2 := NewG2()
+	for i := 0; i < fuz; i++ {
+		p, q := g2.rand(), g2.rand()
+		for _, a := range []*PointG2{p, g2.Zero(), new(PointG2).Set(q), g2.Neg(g2.New(), q)} {
+			for _, b := range []*PointG2{q, g2.Zero()} {
+				expected, r := g2.New(), g2.New()
+				g2.Add(expected, a, b)
+				g2.addCT(r, a, b)
+				if !g2.Equal(expected, r) {
+					t.Fatal("constant time addition failed")
+				}
+			}
+		}
+	}
+}
+
+func TestG1MulScalarCT(t *testing.T) {
+	g := NewG1()
+	scalars := append(mulBaseTestScalars(),
+		new(Fr).setUint64(ctMulRowSize),
+		new(Fr).setUint64(ctMulRowSize+1),
+		new(Fr).setUint64(1<<ctMulWindowSize-1),
+	)
+	for _, p := range []*PointG1{g.rand(), g.One(), g.Zero()} {
+		for i, e := range scalars {
+			expected, r := g.New(), g.New()
+			g.mulScalar(expected, p, e)
+			g.MulScalarCT(r, p, e)
+			if !g.Equal(expected, r) {
+				t.Fatal("constant time scalar multiplication failed", i)
+			}
+		}
+	}
+}
+
+func TestG2MulScalarCT(t *testing.T) {
+	g := NewG2()
+	scalars := append(mulBaseTestScalars(),
+		new(Fr).setUint64(ctMulRowSize),
+		new(Fr).setUint64(ctMulRowSize+1),
+		new(Fr).setUint64(1<<ctMulWindowSize-1),
+	)
+	for _, p := range []*PointG2{g.rand(), g.One(), g.Zero()} {
+		for i, e := range scalars {
+			expected, r := g.New(), g.New()
+			g.mulScalar(expected, p, e)
+			g.MulScalarCT(r, p, e)
+			if !g.Equal(expected, r) {
+				t.Fatal("constant time scalar multiplication failed", i)
+			}
+		}
+	}
+}
+
+func TestDudectG1MulScalarCT(t *testing.T) {
+	g := NewG1()
+	p := g.rand()
+	// zero scalar takes no additions in variable time multiplication
+	fixed := func() []byte { return make([]byte, 32) }
+	random := func() []byte {
+		e, _ := new(Fr).Rand(rand.Reader)
+		return e.ToBytes()
+	}
+	r := g.New()
+	dudectRun(t, "G1 MulScalarCT", fixed, random, func(in []byte) { g.MulScalarCT(r, p, new(Fr).FromBytes(in)) })
+}
+
+func TestDudectG2MulScalarCT(t *testing.T) {
+	g := NewG2()
+	p := g.rand()
+	fixed := func() []byte { return make([]byte, 32) }
+	random := func() []byte {
+		e, _ := new(Fr).Rand(rand.Reader)
+		return e.ToBytes()
+	}
+	r := g.New()
+	dudectRun(t, "G2 MulScalarCT", fixed, random, func(in []byte) { g.MulScalarCT(r, p, new(Fr).FromBytes(in)) })
+}
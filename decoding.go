@@ -0,0 +1,51 @@
+package bls12381
+
+import (
+	"errors"
+)
+
+// Sentinel errors returned by point decoders. Returned errors may wrap them with further details,
+// so they should be tested with errors.Is.
+var (
+	ErrNotOnCurve    = errors.New("point is not on curve")
+	ErrNotInSubgroup = errors.New("point is not on correct subgroup")
+	ErrBadFlags      = errors.New("bad encoding flags")
+	ErrNonCanonical  = errors.New("non canonical encoding")
+)
+
+// DecodePolicy defines which validations are applied while decoding points.
+type DecodePolicy uint8
+
+const (
+	// DecodeStrict rejects bad flags and non canonical encodings
+	// and requires the point to be on curve and in correct subgroup.
+	DecodeStrict DecodePolicy = iota
+	// DecodeNoSubgroupCheck is same as DecodeStrict but skips subgroup check.
+	DecodeNoSubgroupCheck
+	// DecodeUnchecked only parses the input, curve and subgroup checks are skipped.
+	// Flags and field elements are still required to be canonical.
+	// It must be used only for trusted inputs such as previously validated and stored points.
+	// Notice that decompression still fails if there is no point for given x coordinate.
+	DecodeUnchecked
+)
+
+func (p DecodePolicy) checkOnCurve() bool {
+	return p != DecodeUnchecked
+}
+
+func (p DecodePolicy) checkSubgroup() bool {
+	return p == DecodeStrict
+}
+
+// isZeroBytes checks if all bytes are zero except the masked first byte.
+func isZeroBytes(in []byte, firstByteMask byte) bool {
+	if in[0]&firstByteMask != 0 {
+		return false
+	}
+	for i := 1; i < len(in); i++ {
+		if in[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
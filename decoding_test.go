@@ -0,0 +1,225 @@
+package bls12381
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestG1DecodingPolicies(t *testing.T) {
+	g := NewG1()
+	for i := 0; i < fuz; i++ {
+		// points out of subgroup
+		p := g.rand()
+		for _, v := range []struct {
+			name   string
+			in     []byte
+			decode func([]byte, DecodePolicy) (*PointG1, error)
+		}{
+			{"compressed", g.ToCompressed(p), g.DecodeCompressed},
+			{"uncompressed", g.ToUncompressed(p), g.DecodeUncompressed},
+			{"bytes", g.ToBytes(p), g.DecodeBytes},
+		} {
+			if _, err := v.decode(v.in, DecodeStrict); !errors.Is(err, ErrNotInSubgroup) {
+				t.Fatal(v.name, "point out of subgroup must be rejected", err)
+			}
+			for _, policy := range []DecodePolicy{DecodeNoSubgroupCheck, DecodeUnchecked} {
+				r, err := v.decode(v.in, policy)
+				if err != nil {
+					t.Fatal(v.name, err)
+				}
+				if !g.Equal(r, p) {
+					t.Fatal(v.name, "decoding failed")
+				}
+			}
+		}
+		// points not on curve
+		q := g.Affine(g.randCorrect())
+		add(&q[1], &q[1], new(fe).one())
+		for _, v := range []struct {
+			name   string
+			in     []byte
+			decode func([]byte, DecodePolicy) (*PointG1, error)
+		}{
+			{"uncompressed", g.ToUncompressed(q), g.DecodeUncompressed},
+			{"bytes", g.ToBytes(q), g.DecodeBytes},
+		} {
+			for _, policy := range []DecodePolicy{DecodeStrict, DecodeNoSubgroupCheck} {
+				if _, err := v.decode(v.in, policy); !errors.Is(err, ErrNotOnCurve) {
+					t.Fatal(v.name, "point not on curve must be rejected", err)
+				}
+			}
+			r, err := v.decode(v.in, DecodeUnchecked)
+			if err != nil {
+				t.Fatal(v.name, err)
+			}
+			if !r[0].equal(&q[0]) || !r[1].equal(&q[1]) {
+				t.Fatal(v.name, "unchecked decoding failed")
+			}
+		}
+	}
+	compressed := g.ToCompressed(g.randCorrect())
+	uncompressed := g.ToUncompressed(g.randCorrect())
+	// bad flags
+	{
+		in := append([]byte{}, compressed...)
+		in[0] &= 0x7f
+		if _, err := g.DecodeCompressed(in, DecodeUnchecked); !errors.Is(err, ErrBadFlags) {
+			t.Fatal("compression flag must be checked", err)
+		}
+		in = g.ToCompressed(g.Zero())
+		in[0] |= 1 << 5
+		if _, err := g.DecodeCompressed(in, DecodeUnchecked); !errors.Is(err, ErrBadFlags) {
+			t.Fatal("sort flag must be checked for infinity", err)
+		}
+		in = append([]byte{}, uncompressed...)
+		in[0] |= 1 << 7
+		if _, err := g.DecodeUncompressed(in, DecodeUnchecked); !errors.Is(err, ErrBadFlags) {
+			t.Fatal("compression flag must be checked", err)
+		}
+		in = append([]byte{}, uncompressed...)
+		in[0] |= 1 << 5
+		if _, err := g.DecodeUncompressed(in, DecodeUnchecked); !errors.Is(err, ErrBadFlags) {
+			t.Fatal("sort flag must be checked", err)
+		}
+	}
+	// non canonical encodings
+	{
+		in := g.ToCompressed(g.Zero())
+		in[len(in)-1] = 1
+		if _, err := g.DecodeCompressed(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("infinity must be canonical", err)
+		}
+		in = g.ToUncompressed(g.Zero())
+		in[len(in)-1] = 1
+		if _, err := g.DecodeUncompressed(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("infinity must be canonical", err)
+		}
+		in = make([]byte, fpByteSize)
+		for i := range in {
+			in[i] = 0xff
+		}
+		in[0] = 0x9f
+		if _, err := g.DecodeCompressed(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("field element must be less than modulus", err)
+		}
+		in = append([]byte{}, uncompressed...)
+		for i := fpByteSize; i < 2*fpByteSize; i++ {
+			in[i] = 0xff
+		}
+		if _, err := g.DecodeUncompressed(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("field element must be less than modulus", err)
+		}
+		if _, err := g.DecodeBytes(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("field element must be less than modulus", err)
+		}
+	}
+}
+
+func TestG2DecodingPolicies(t *testing.T) {
+	g := NewG2()
+	for i := 0; i < fuz; i++ {
+		// points out of subgroup
+		p := g.rand()
+		for _, v := range []struct {
+			name   string
+			in     []byte
+			decode func([]byte, DecodePolicy) (*PointG2, error)
+		}{
+			{"compressed", g.ToCompressed(p), g.DecodeCompressed},
+			{"uncompressed", g.ToUncompressed(p), g.DecodeUncompressed},
+			{"bytes", g.ToBytes(p), g.DecodeBytes},
+		} {
+			if _, err := v.decode(v.in, DecodeStrict); !errors.Is(err, ErrNotInSubgroup) {
+				t.Fatal(v.name, "point out of subgroup must be rejected", err)
+			}
+			for _, policy := range []DecodePolicy{DecodeNoSubgroupCheck, DecodeUnchecked} {
+				r, err := v.decode(v.in, policy)
+				if err != nil {
+					t.Fatal(v.name, err)
+				}
+				if !g.Equal(r, p) {
+					t.Fatal(v.name, "decoding failed")
+				}
+			}
+		}
+		// points not on curve
+		q := g.Affine(g.randCorrect())
+		fp2Add(&q[1], &q[1], new(fe2).one())
+		for _, v := range []struct {
+			name   string
+			in     []byte
+			decode func([]byte, DecodePolicy) (*PointG2, error)
+		}{
+			{"uncompressed", g.ToUncompressed(q), g.DecodeUncompressed},
+			{"bytes", g.ToBytes(q), g.DecodeBytes},
+		} {
+			for _, policy := range []DecodePolicy{DecodeStrict, DecodeNoSubgroupCheck} {
+				if _, err := v.decode(v.in, policy); !errors.Is(err, ErrNotOnCurve) {
+					t.Fatal(v.name, "point not on curve must be rejected", err)
+				}
+			}
+			r, err := v.decode(v.in, DecodeUnchecked)
+			if err != nil {
+				t.Fatal(v.name, err)
+			}
+			if !r[0].equal(&q[0]) || !r[1].equal(&q[1]) {
+				t.Fatal(v.name, "unchecked decoding failed")
+			}
+		}
+	}
+	compressed := g.ToCompressed(g.randCorrect())
+	uncompressed := g.ToUncompressed(g.randCorrect())
+	// bad flags
+	{
+		in := append([]byte{}, compressed...)
+		in[0] &= 0x7f
+		if _, err := g.DecodeCompressed(in, DecodeUnchecked); !errors.Is(err, ErrBadFlags) {
+			t.Fatal("compression flag must be checked", err)
+		}
+		in = g.ToCompressed(g.Zero())
+		in[0] |= 1 << 5
+		if _, err := g.DecodeCompressed(in, DecodeUnchecked); !errors.Is(err, ErrBadFlags) {
+			t.Fatal("sort flag must be checked for infinity", err)
+		}
+		in = append([]byte{}, uncompressed...)
+		in[0] |= 1 << 7
+		if _, err := g.DecodeUncompressed(in, DecodeUnchecked); !errors.Is(err, ErrBadFlags) {
+			t.Fatal("compression flag must be checked", err)
+		}
+		in = append([]byte{}, uncompressed...)
+		in[0] |= 1 << 5
+		if _, err := g.DecodeUncompressed(in, DecodeUnchecked); !errors.Is(err, ErrBadFlags) {
+			t.Fatal("sort flag must be checked", err)
+		}
+	}
+	// non canonical encodings
+	{
+		in := g.ToCompressed(g.Zero())
+		in[len(in)-1] = 1
+		if _, err := g.DecodeCompressed(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("infinity must be canonical", err)
+		}
+		in = g.ToUncompressed(g.Zero())
+		in[len(in)-1] = 1
+		if _, err := g.DecodeUncompressed(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("infinity must be canonical", err)
+		}
+		in = append([]byte{}, compressed...)
+		for i := fpByteSize; i < 2*fpByteSize; i++ {
+			in[i] = 0xff
+		}
+		if _, err := g.DecodeCompressed(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("field element must be less than modulus", err)
+		}
+		in = append([]byte{}, uncompressed...)
+		for i := 3 * fpByteSize; i < 4*fpByteSize; i++ {
+			in[i] = 0xff
+		}
+		if _, err := g.DecodeUncompressed(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("field element must be less than modulus", err)
+		}
+		if _, err := g.DecodeBytes(in, DecodeUnchecked); !errors.Is(err, ErrNonCanonical) {
+			t.Fatal("field element must be less than modulus", err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package bls12381
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Batch decompression splits inputs into chunks which are processed concurrently.
+// Each worker uses its own group instance since groups hold temporary values.
+// A worker parses its inputs first and then solves curve equations of all of them together.
+// Square roots in Fp2 need an inversion which is shared with Montgomery's trick.
+// Square roots in Fp are single exponentiations since p = 3 mod 4, so there is no inversion to share in G1.
+//
+// Every point is checked to be in correct subgroup on its own. A check over a random linear combination
+// of the points is not offered. All prime factors of G1 cofactor and the smallest ones of G2 cofactor
+// are small, for example a point with a component of order 3 in G1 passes such a check whenever
+// its coefficient is a multiple of 3, which happens with probability 1/3 for any size of coefficients.
+// Clearing the cofactor before combining does not help either, since a point out of the subgroup
+// is then accepted whenever its cofactor multiple is in the subgroup, which is always the case in G1.
+
+// FromCompressedBatch decompresses given points concurrently.
+// Returned points and errors are in the same order with the inputs. For each index either a point or an error is returned
+// and results are identical to calling FromCompressed for each input.
+func (g *G1) FromCompressedBatch(in [][]byte) ([]*PointG1, []error) {
+	points, errs := make([]*PointG1, len(in)), make([]error, len(in))
+	runConcurrent(len(in), func(from, to int) {
+		NewG1().fromCompressedBatch(points[from:to], errs[from:to], in[from:to])
+	})
+	return points, errs
+}
+
+func (g *G1) fromCompressedBatch(points []*PointG1, errs []error, in [][]byte) {
+	xs, signs, indexes := []*fe{}, []bool{}, []int{}
+	for i := range in {
+		x, a, err := g.parseCompressed(in[i])
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if x == nil {
+			points[i] = g.Zero()
+			continue
+		}
+		xs, signs, indexes = append(xs, x), append(signs, a), append(indexes, i)
+	}
+	// solve curve equations
+	ys := make([]fe, len(xs))
+	for k, x := range xs {
+		square(&ys[k], x)
+		mul(&ys[k], &ys[k], x)
+		add(&ys[k], &ys[k], b)
+	}
+	for k, i := range indexes {
+		y := &ys[k]
+		if !sqrt(y, y) {
+			errs[i] = ErrNotOnCurve
+			continue
+		}
+		if y.signBE() == signs[k] {
+			neg(y, y)
+		}
+		p := &PointG1{*xs[k], *y, *new(fe).one()}
+		if !g.InCorrectSubgroup(p) {
+			errs[i] = ErrNotInSubgroup
+			continue
+		}
+		points[i] = p
+	}
+}
+
+// FromCompressedBatch decompresses given points concurrently.
+// Returned points and errors are in the same order with the inputs. For each index either a point or an error is returned
+// and results are identical to calling FromCompressed for each input.
+// Square roots are calculated with a single shared inversion per worker.
+func (g *G2) FromCompressedBatch(in [][]byte) ([]*PointG2, []error) {
+	points, errs := make([]*PointG2, len(in)), make([]error, len(in))
+	runConcurrent(len(in), func(from, to int) {
+		NewG2().fromCompressedBatch(points[from:to], errs[from:to], in[from:to])
+	})
+	return points, errs
+}
+
+func (g *G2) fromCompressedBatch(points []*PointG2, errs []error, in [][]byte) {
+	xs, signs, indexes := []*fe2{}, []bool{}, []int{}
+	for i := range in {
+		x, a, err := g.parseCompressed(in[i])
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if x == nil {
+			points[i] = g.Zero()
+			continue
+		}
+		xs, signs, indexes = append(xs, x), append(signs, a), append(indexes, i)
+	}
+	// solve curve equations
+	ys, roots := make([]fe2, len(xs)), make([]fe2, len(xs))
+	for i, x := range xs {
+		g.f.square(&ys[i], x)
+		g.f.mul(&ys[i], &ys[i], x)
+		fp2Add(&ys[i], &ys[i], b2)
+	}
+	ok := g.f.sqrtBatch(roots, ys)
+	for k, i := range indexes {
+		if !ok[k] {
+			errs[i] = ErrNotOnCurve
+			continue
+		}
+		y := &roots[k]
+		if y.signBE() == signs[k] {
+			fp2Neg(y, y)
+		}
+		p := &PointG2{*xs[k], *y, *new(fe2).one()}
+		if !g.InCorrectSubgroup(p) {
+			errs[i] = ErrNotInSubgroup
+			continue
+		}
+		points[i] = p
+	}
+}
+
+// runConcurrent splits n jobs into contiguous chunks and runs each chunk in a goroutine.
+func runConcurrent(n int, job func(from, to int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		if n > 0 {
+			job(0, n)
+		}
+		return
+	}
+	chunk := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for from := 0; from < n; from += chunk {
+		to := from + chunk
+		if to > n {
+			to = n
+		}
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			job(from, to)
+		}(from, to)
+	}
+	wg.Wait()
+}
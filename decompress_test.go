@@ -0,0 +1,151 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestG1FromCompressedBatch(t *testing.T) {
+	g := NewG1()
+	n := 100
+	in := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		in[i] = g.ToCompressed(g.randCorrect())
+	}
+	points, errs := g.FromCompressedBatch(in)
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		expected, _ := g.FromCompressed(in[i])
+		if !g.Equal(points[i], expected) {
+			t.Fatal("batch decompression failed")
+		}
+	}
+	// invalid inputs
+	in[1] = g.ToCompressed(g.rand())
+	in[7] = g.ToCompressed(g.Zero())
+	in[13] = in[13][1:]
+	in[17][0] &= 0x7f
+	in[n-1] = g.ToCompressed(g.rand())
+	// point with a component of order 3, (0, 2) is a point of order 3
+	two := new(fe).one()
+	add(two, two, two)
+	torsion := &PointG1{*new(fe).zero(), *two, *new(fe).one()}
+	in[29] = g.ToCompressed(g.Add(g.New(), g.randCorrect(), torsion))
+	for {
+		// x such that x^3 + b is not square
+		x, _ := new(fe).rand(rand.Reader)
+		y := new(fe)
+		square(y, x)
+		mul(y, y, x)
+		add(y, y, b)
+		if !sqrt(y, y) {
+			in[23] = toBytes(x)
+			in[23][0] |= 1 << 7
+			break
+		}
+	}
+	points, errs = g.FromCompressedBatch(in)
+	for i := 0; i < n; i++ {
+		expected, err := g.FromCompressed(in[i])
+		if (err == nil) != (errs[i] == nil) || (err != nil && err.Error() != errs[i].Error()) {
+			t.Fatal("batch decompression error mismatch", i, err, errs[i])
+		}
+		if err != nil {
+			if points[i] != nil {
+				t.Fatal("point must be nil for failed index", i)
+			}
+			continue
+		}
+		if !g.Equal(points[i], expected) {
+			t.Fatal("batch decompression failed", i)
+		}
+	}
+	if errs[29] != ErrNotInSubgroup {
+		t.Fatal("point with small torsion component must be rejected")
+	}
+	points, errs = g.FromCompressedBatch(nil)
+	if len(points) != 0 || len(errs) != 0 {
+		t.Fatal("empty batch")
+	}
+}
+
+func TestG2FromCompressedBatch(t *testing.T) {
+	g := NewG2()
+	n := 100
+	in := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		in[i] = g.ToCompressed(g.randCorrect())
+	}
+	points, errs := g.FromCompressedBatch(in)
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		expected, _ := g.FromCompressed(in[i])
+		if !g.Equal(points[i], expected) {
+			t.Fatal("batch decompression failed")
+		}
+	}
+	// invalid inputs
+	in[1] = g.ToCompressed(g.rand())
+	in[7] = g.ToCompressed(g.Zero())
+	in[13] = in[13][1:]
+	in[17][0] &= 0x7f
+	in[n-1] = g.ToCompressed(g.rand())
+	for {
+		// x such that x^3 + b is not square
+		x, _ := new(fe2).rand(rand.Reader)
+		y := new(fe2)
+		g.f.square(y, x)
+		g.f.mul(y, y, x)
+		fp2Add(y, y, b2)
+		if !g.f.sqrt(y, y) {
+			in[23] = g.f.toBytes(x)
+			in[23][0] |= 1 << 7
+			break
+		}
+	}
+	points, errs = g.FromCompressedBatch(in)
+	for i := 0; i < n; i++ {
+		expected, err := g.FromCompressed(in[i])
+		if (err == nil) != (errs[i] == nil) || (err != nil && err.Error() != errs[i].Error()) {
+			t.Fatal("batch decompression error mismatch", i, err, errs[i])
+		}
+		if err != nil {
+			if points[i] != nil {
+				t.Fatal("point must be nil for failed index", i)
+			}
+			continue
+		}
+		if !g.Equal(points[i], expected) {
+			t.Fatal("batch decompression failed", i)
+		}
+	}
+	points, errs = g.FromCompressedBatch(nil)
+	if len(points) != 0 || len(errs) != 0 {
+		t.Fatal("empty batch")
+	}
+}
+
+func BenchmarkG2FromCompressed(t *testing.B) {
+	g := NewG2()
+	n := 256
+	in := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		in[i] = g.ToCompressed(g.randCorrect())
+	}
+	t.Run("sequential", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < n; j++ {
+				g.FromCompressed(in[j])
+			}
+		}
+	})
+	t.Run("batch", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.FromCompressedBatch(in)
+		}
+	})
+}
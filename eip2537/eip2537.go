@@ -0,0 +1,280 @@
+// Package eip2537 implements BLS12-381 precompile operations as defined in EIP-2537.
+// https://eips.ethereum.org/EIPS/eip-2537
+//
+// Field elements are encoded as 64 bytes big endian integers where top 16 bytes must be zero.
+// G1 points are encoded in 128 bytes as (x, y) and G2 points are encoded in 256 bytes as
+// (x.c0, x.c1, y.c0, y.c1). Point at infinity is encoded as all zeros.
+package eip2537
+
+import (
+	"errors"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+const (
+	fpSize       = 48
+	paddingSize  = 16
+	paddedFpSize = fpSize + paddingSize
+	scalarSize   = 32
+	g1Size       = 2 * paddedFpSize
+	g2Size       = 4 * paddedFpSize
+	g1MSMPairLen = g1Size + scalarSize
+	g2MSMPairLen = g2Size + scalarSize
+	pairingLen   = g1Size + g2Size
+)
+
+var (
+	ErrInvalidInputLength          = errors.New("invalid input length")
+	ErrInvalidFieldElementTopBytes = errors.New("invalid field element top bytes")
+	ErrInvalidFieldElement         = errors.New("invalid field element, must be less than modulus")
+	ErrPointNotOnCurve             = errors.New("invalid point: not on curve")
+	ErrG1PointSubgroup             = errors.New("g1 point is not on correct subgroup")
+	ErrG2PointSubgroup             = errors.New("g2 point is not on correct subgroup")
+)
+
+// Precompile is the interface of a precompiled contract.
+type Precompile interface {
+	// RequiredGas returns the gas required to execute the precompile with given input.
+	RequiredGas(input []byte) uint64
+	// Run executes the precompile.
+	Run(input []byte) ([]byte, error)
+}
+
+// Precompiles maps precompile addresses to their implementations.
+var Precompiles = map[byte]Precompile{
+	0x0b: &G1Add{},
+	0x0c: &G1MSM{},
+	0x0d: &G2Add{},
+	0x0e: &G2MSM{},
+	0x0f: &PairingCheck{},
+	0x10: &MapFpToG1{},
+	0x11: &MapFp2ToG2{},
+}
+
+// G1Add implements G1ADD precompile.
+// Input is 256 bytes concatenation of two G1 points. Output is a single G1 point in 128 bytes.
+// Points are required to be on curve but subgroup check is not applied.
+type G1Add struct{}
+
+// RequiredGas returns the gas required to execute G1ADD.
+func (c *G1Add) RequiredGas(input []byte) uint64 {
+	return G1AddGas
+}
+
+// Run executes G1ADD.
+func (c *G1Add) Run(input []byte) ([]byte, error) {
+	if len(input) != 2*g1Size {
+		return nil, ErrInvalidInputLength
+	}
+	g := bls12381.NewG1()
+	p0, err := decodeG1(g, input[:g1Size])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := decodeG1(g, input[g1Size:])
+	if err != nil {
+		return nil, err
+	}
+	g.Add(p0, p0, p1)
+	return encodeG1(g, p0), nil
+}
+
+// G1MSM implements G1MSM precompile.
+// Input is concatenation of k slices of G1 point in 128 bytes and scalar in 32 bytes.
+// Output is a single G1 point in 128 bytes. Points are required to be in correct subgroup.
+type G1MSM struct{}
+
+// RequiredGas returns the gas required to execute G1MSM.
+func (c *G1MSM) RequiredGas(input []byte) uint64 {
+	return msmGas(len(input)/g1MSMPairLen, G1MulGas, g1MSMDiscountTable[:])
+}
+
+// Run executes G1MSM.
+func (c *G1MSM) Run(input []byte) ([]byte, error) {
+	k := len(input) / g1MSMPairLen
+	if len(input) == 0 || len(input)%g1MSMPairLen != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	g := bls12381.NewG1()
+	points := make([]*bls12381.PointG1, k)
+	scalars := make([]*bls12381.Fr, k)
+	for i := 0; i < k; i++ {
+		off := i * g1MSMPairLen
+		p, err := decodeG1(g, input[off:off+g1Size])
+		if err != nil {
+			return nil, err
+		}
+		if !g.InCorrectSubgroup(p) {
+			return nil, ErrG1PointSubgroup
+		}
+		points[i] = p
+		scalars[i] = decodeScalar(input[off+g1Size : off+g1MSMPairLen])
+	}
+	r := g.New()
+	if _, err := g.MultiExp(r, points, scalars); err != nil {
+		return nil, err
+	}
+	return encodeG1(g, r), nil
+}
+
+// G2Add implements G2ADD precompile.
+// Input is 512 bytes concatenation of two G2 points. Output is a single G2 point in 256 bytes.
+// Points are required to be on curve but subgroup check is not applied.
+type G2Add struct{}
+
+// RequiredGas returns the gas required to execute G2ADD.
+func (c *G2Add) RequiredGas(input []byte) uint64 {
+	return G2AddGas
+}
+
+// Run executes G2ADD.
+func (c *G2Add) Run(input []byte) ([]byte, error) {
+	if len(input) != 2*g2Size {
+		return nil, ErrInvalidInputLength
+	}
+	g := bls12381.NewG2()
+	p0, err := decodeG2(g, input[:g2Size])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := decodeG2(g, input[g2Size:])
+	if err != nil {
+		return nil, err
+	}
+	g.Add(p0, p0, p1)
+	return encodeG2(g, p0), nil
+}
+
+// G2MSM implements G2MSM precompile.
+// Input is concatenation of k slices of G2 point in 256 bytes and scalar in 32 bytes.
+// Output is a single G2 point in 256 bytes. Points are required to be in correct subgroup.
+type G2MSM struct{}
+
+// RequiredGas returns the gas required to execute G2MSM.
+func (c *G2MSM) RequiredGas(input []byte) uint64 {
+	return msmGas(len(input)/g2MSMPairLen, G2MulGas, g2MSMDiscountTable[:])
+}
+
+// Run executes G2MSM.
+func (c *G2MSM) Run(input []byte) ([]byte, error) {
+	k := len(input) / g2MSMPairLen
+	if len(input) == 0 || len(input)%g2MSMPairLen != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	g := bls12381.NewG2()
+	points := make([]*bls12381.PointG2, k)
+	scalars := make([]*bls12381.Fr, k)
+	for i := 0; i < k; i++ {
+		off := i * g2MSMPairLen
+		p, err := decodeG2(g, input[off:off+g2Size])
+		if err != nil {
+			return nil, err
+		}
+		if !g.InCorrectSubgroup(p) {
+			return nil, ErrG2PointSubgroup
+		}
+		points[i] = p
+		scalars[i] = decodeScalar(input[off+g2Size : off+g2MSMPairLen])
+	}
+	r := g.New()
+	if _, err := g.MultiExp(r, points, scalars); err != nil {
+		return nil, err
+	}
+	return encodeG2(g, r), nil
+}
+
+// PairingCheck implements PAIRING_CHECK precompile.
+// Input is concatenation of k slices of G1 point in 128 bytes and G2 point in 256 bytes.
+// Output is 32 bytes where the last byte is 0x01 if the pairing product equals to one, otherwise 0x00.
+// Points are required to be in correct subgroup.
+type PairingCheck struct{}
+
+// RequiredGas returns the gas required to execute PAIRING_CHECK.
+func (c *PairingCheck) RequiredGas(input []byte) uint64 {
+	return PairingBaseGas + uint64(len(input)/pairingLen)*PairingPerPairGas
+}
+
+// Run executes PAIRING_CHECK.
+func (c *PairingCheck) Run(input []byte) ([]byte, error) {
+	k := len(input) / pairingLen
+	if len(input) == 0 || len(input)%pairingLen != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	e := bls12381.NewEngine()
+	for i := 0; i < k; i++ {
+		off := i * pairingLen
+		p1, err := decodeG1(e.G1, input[off:off+g1Size])
+		if err != nil {
+			return nil, err
+		}
+		p2, err := decodeG2(e.G2, input[off+g1Size:off+pairingLen])
+		if err != nil {
+			return nil, err
+		}
+		if !e.G1.InCorrectSubgroup(p1) {
+			return nil, ErrG1PointSubgroup
+		}
+		if !e.G2.InCorrectSubgroup(p2) {
+			return nil, ErrG2PointSubgroup
+		}
+		e.AddPair(p1, p2)
+	}
+	out := make([]byte, 32)
+	if e.Check() {
+		out[31] = 1
+	}
+	return out, nil
+}
+
+// MapFpToG1 implements MAP_FP_TO_G1 precompile.
+// Input is a field element in 64 bytes. Output is a G1 point in 128 bytes.
+type MapFpToG1 struct{}
+
+// RequiredGas returns the gas required to execute MAP_FP_TO_G1.
+func (c *MapFpToG1) RequiredGas(input []byte) uint64 {
+	return MapFpToG1Gas
+}
+
+// Run executes MAP_FP_TO_G1.
+func (c *MapFpToG1) Run(input []byte) ([]byte, error) {
+	if len(input) != paddedFpSize {
+		return nil, ErrInvalidInputLength
+	}
+	u, err := decodeFieldElement(input)
+	if err != nil {
+		return nil, err
+	}
+	g := bls12381.NewG1()
+	p, err := g.MapToCurve(u)
+	if err != nil {
+		return nil, err
+	}
+	return encodeG1(g, p), nil
+}
+
+// MapFp2ToG2 implements MAP_FP2_TO_G2 precompile.
+// Input is a quadratic extension field element in 128 bytes. Output is a G2 point in 256 bytes.
+type MapFp2ToG2 struct{}
+
+// RequiredGas returns the gas required to execute MAP_FP2_TO_G2.
+func (c *MapFp2ToG2) RequiredGas(input []byte) uint64 {
+	return MapFp2ToG2Gas
+}
+
+// Run executes MAP_FP2_TO_G2.
+func (c *MapFp2ToG2) Run(input []byte) ([]byte, error) {
+	if len(input) != 2*paddedFpSize {
+		return nil, ErrInvalidInputLength
+	}
+	u, err := decodeFieldElement2(input)
+	if err != nil {
+		return nil, err
+	}
+	g := bls12381.NewG2()
+	p, err := g.MapToCurve(u)
+	if err != nil {
+		return nil, err
+	}
+	return encodeG2(g, p), nil
+}
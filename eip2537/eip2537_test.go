@@ -0,0 +1,171 @@
+package eip2537
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+// Test vectors are copied from go-ethereum @ v1.15.0
+// core/vm/testdata/precompiles
+
+type vector struct {
+	Input    string
+	Expected string
+	Name     string
+	Gas      uint64
+}
+
+type failVector struct {
+	Input         string
+	ExpectedError string
+	Name          string
+}
+
+func readVectors(t *testing.T, name string, v interface{}) {
+	data, err := ioutil.ReadFile("../tests/eip2537/" + name + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testVectors(t *testing.T, name string, p Precompile) {
+	var vectors []vector
+	readVectors(t, name, &vectors)
+	for _, v := range vectors {
+		in, err := hex.DecodeString(v.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, err := hex.DecodeString(v.Expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gas := p.RequiredGas(in); gas != v.Gas {
+			t.Fatalf("%s: gas mismatch, expected %d, have %d", v.Name, v.Gas, gas)
+		}
+		out, err := p.Run(in)
+		if err != nil {
+			t.Fatal(v.Name, err)
+		}
+		if !bytes.Equal(out, expected) {
+			t.Fatalf("%s: output mismatch", v.Name)
+		}
+	}
+}
+
+func testFailVectors(t *testing.T, name string, p Precompile) {
+	var vectors []failVector
+	readVectors(t, "fail-"+name, &vectors)
+	for _, v := range vectors {
+		in, err := hex.DecodeString(v.Input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.Run(in); err == nil {
+			t.Fatalf("%s: expected to fail with %q", v.Name, v.ExpectedError)
+		}
+	}
+}
+
+func TestG1Add(t *testing.T) {
+	testVectors(t, "blsG1Add", Precompiles[0x0b])
+	testFailVectors(t, "blsG1Add", Precompiles[0x0b])
+}
+
+func TestG1MSM(t *testing.T) {
+	testVectors(t, "blsG1Mul", Precompiles[0x0c])
+	testFailVectors(t, "blsG1Mul", Precompiles[0x0c])
+	testVectors(t, "blsG1MultiExp", Precompiles[0x0c])
+	testFailVectors(t, "blsG1MultiExp", Precompiles[0x0c])
+}
+
+func TestG2Add(t *testing.T) {
+	testVectors(t, "blsG2Add", Precompiles[0x0d])
+	testFailVectors(t, "blsG2Add", Precompiles[0x0d])
+}
+
+func TestG2MSM(t *testing.T) {
+	testVectors(t, "blsG2Mul", Precompiles[0x0e])
+	testFailVectors(t, "blsG2Mul", Precompiles[0x0e])
+	testVectors(t, "blsG2MultiExp", Precompiles[0x0e])
+	testFailVectors(t, "blsG2MultiExp", Precompiles[0x0e])
+}
+
+func TestPairingCheck(t *testing.T) {
+	testVectors(t, "blsPairing", Precompiles[0x0f])
+	testFailVectors(t, "blsPairing", Precompiles[0x0f])
+}
+
+func TestMapFpToG1(t *testing.T) {
+	testVectors(t, "blsMapG1", Precompiles[0x10])
+	testFailVectors(t, "blsMapG1", Precompiles[0x10])
+}
+
+func TestMapFp2ToG2(t *testing.T) {
+	testVectors(t, "blsMapG2", Precompiles[0x11])
+	testFailVectors(t, "blsMapG2", Precompiles[0x11])
+}
+
+func TestMSMGas(t *testing.T) {
+	for _, v := range []struct {
+		k      int
+		g1, g2 uint64
+	}{
+		{0, 0, 0},
+		{1, 12000, 22500},
+		{2, 2 * 12000 * 949 / 1000, 2 * 22500 * 1000 / 1000},
+		{128, 128 * 12000 * 519 / 1000, 128 * 22500 * 524 / 1000},
+		{129, 129 * 12000 * 519 / 1000, 129 * 22500 * 524 / 1000},
+	} {
+		if gas := new(G1MSM).RequiredGas(make([]byte, v.k*g1MSMPairLen)); gas != v.g1 {
+			t.Fatal("bad g1 msm gas", v.k, gas)
+		}
+		if gas := new(G2MSM).RequiredGas(make([]byte, v.k*g2MSMPairLen)); gas != v.g2 {
+			t.Fatal("bad g2 msm gas", v.k, gas)
+		}
+	}
+}
+
+func TestSubgroupCheckRules(t *testing.T) {
+	// points which are on curve but not in correct subgroup are accepted
+	// by addition precompiles and rejected by msm and pairing precompiles
+	var vectors []failVector
+	readVectors(t, "fail-blsG1MultiExp", &vectors)
+	for _, v := range vectors {
+		if v.Name != "bls_g1multiexp_g1_not_in_correct_subgroup" {
+			continue
+		}
+		in, _ := hex.DecodeString(v.Input)
+		if _, err := new(G1MSM).Run(in); err != ErrG1PointSubgroup {
+			t.Fatal("subgroup check is expected to fail", err)
+		}
+		for i := 0; i < len(in)/g1MSMPairLen; i++ {
+			p := in[i*g1MSMPairLen : i*g1MSMPairLen+g1Size]
+			if _, err := new(G1Add).Run(append(append([]byte{}, p...), p...)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	readVectors(t, "fail-blsG2MultiExp", &vectors)
+	for _, v := range vectors {
+		if v.Name != "bls_pairing_g2_not_in_correct_subgroup" {
+			continue
+		}
+		in, _ := hex.DecodeString(v.Input)
+		if _, err := new(G2MSM).Run(in); err != ErrG2PointSubgroup {
+			t.Fatal("subgroup check is expected to fail", err)
+		}
+		for i := 0; i < len(in)/g2MSMPairLen; i++ {
+			p := in[i*g2MSMPairLen : i*g2MSMPairLen+g2Size]
+			if _, err := new(G2Add).Run(append(append([]byte{}, p...), p...)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
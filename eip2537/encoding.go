@@ -0,0 +1,130 @@
+package eip2537
+
+import (
+	"bytes"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// modulus is big endian encoding of base field modulus
+// p = 0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab
+var modulus = []byte{
+	0x1a, 0x01, 0x11, 0xea, 0x39, 0x7f, 0xe6, 0x9a, 0x4b, 0x1b, 0xa7, 0xb6, 0x43, 0x4b, 0xac, 0xd7,
+	0x64, 0x77, 0x4b, 0x84, 0xf3, 0x85, 0x12, 0xbf, 0x67, 0x30, 0xd2, 0xa0, 0xf6, 0xb0, 0xf6, 0x24,
+	0x1e, 0xab, 0xff, 0xfe, 0xb1, 0x53, 0xff, 0xff, 0xb9, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xaa, 0xab,
+}
+
+// decodeFieldElement expects 64 byte padded input and returns 48 byte field element encoding.
+// Top 16 bytes of the input must be zero and remaining 48 bytes must be less than modulus.
+func decodeFieldElement(in []byte) ([]byte, error) {
+	if len(in) != paddedFpSize {
+		return nil, ErrInvalidInputLength
+	}
+	for i := 0; i < paddingSize; i++ {
+		if in[i] != 0x00 {
+			return nil, ErrInvalidFieldElementTopBytes
+		}
+	}
+	out := in[paddingSize:]
+	if bytes.Compare(out, modulus) != -1 {
+		return nil, ErrInvalidFieldElement
+	}
+	return out, nil
+}
+
+// decodeFieldElement2 expects 128 byte input in (c0, c1) order and
+// returns 96 byte encoding in (c1, c0) order as it is used in base library.
+func decodeFieldElement2(in []byte) ([]byte, error) {
+	if len(in) != 2*paddedFpSize {
+		return nil, ErrInvalidInputLength
+	}
+	c0, err := decodeFieldElement(in[:paddedFpSize])
+	if err != nil {
+		return nil, err
+	}
+	c1, err := decodeFieldElement(in[paddedFpSize:])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 2*fpSize)
+	copy(out[:fpSize], c1)
+	copy(out[fpSize:], c0)
+	return out, nil
+}
+
+// decodeG1 given 128 bytes input returns a G1 point which is on curve.
+// Point at infinity is expected to be encoded as all zeros.
+// Subgroup check is not applied.
+func decodeG1(g *bls12381.G1, in []byte) (*bls12381.PointG1, error) {
+	if len(in) != g1Size {
+		return nil, ErrInvalidInputLength
+	}
+	x, err := decodeFieldElement(in[:paddedFpSize])
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeFieldElement(in[paddedFpSize:])
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, 2*fpSize)
+	copy(raw[:fpSize], x)
+	copy(raw[fpSize:], y)
+	// field elements are already validated, so the only failure left is the curve equation
+	p, err := g.FromBytes(raw)
+	if err != nil {
+		return nil, ErrPointNotOnCurve
+	}
+	return p, nil
+}
+
+// decodeG2 given 256 bytes input returns a G2 point which is on curve.
+// Point at infinity is expected to be encoded as all zeros.
+// Subgroup check is not applied.
+func decodeG2(g *bls12381.G2, in []byte) (*bls12381.PointG2, error) {
+	if len(in) != g2Size {
+		return nil, ErrInvalidInputLength
+	}
+	x, err := decodeFieldElement2(in[:2*paddedFpSize])
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeFieldElement2(in[2*paddedFpSize:])
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, 4*fpSize)
+	copy(raw[:2*fpSize], x)
+	copy(raw[2*fpSize:], y)
+	// field elements are already validated, so the only failure left is the curve equation
+	p, err := g.FromBytes(raw)
+	if err != nil {
+		return nil, ErrPointNotOnCurve
+	}
+	return p, nil
+}
+
+// decodeScalar decodes 32 bytes big endian scalar. Scalar is not required to be less than group order.
+func decodeScalar(in []byte) *bls12381.Fr {
+	return bls12381.NewFr().FromBytes(in)
+}
+
+// encodeG1 encodes a G1 point into 128 bytes.
+func encodeG1(g *bls12381.G1, p *bls12381.PointG1) []byte {
+	out := make([]byte, g1Size)
+	raw := g.ToBytes(p)
+	copy(out[paddingSize:paddedFpSize], raw[:fpSize])
+	copy(out[paddedFpSize+paddingSize:], raw[fpSize:])
+	return out
+}
+
+// encodeG2 encodes a G2 point into 256 bytes.
+func encodeG2(g *bls12381.G2, p *bls12381.PointG2) []byte {
+	out := make([]byte, g2Size)
+	raw := g.ToBytes(p)
+	// base library encodes fp2 elements in (c1, c0) order
+	for i, j := range []int{1, 0, 3, 2} {
+		copy(out[i*paddedFpSize+paddingSize:(i+1)*paddedFpSize], raw[j*fpSize:(j+1)*fpSize])
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package eip2537
+
+// Gas costs of precompile operations
+const (
+	G1AddGas          uint64 = 375
+	G1MulGas          uint64 = 12000
+	G2AddGas          uint64 = 600
+	G2MulGas          uint64 = 22500
+	PairingBaseGas    uint64 = 37700
+	PairingPerPairGas uint64 = 32600
+	MapFpToG1Gas      uint64 = 5500
+	MapFp2ToG2Gas     uint64 = 23800
+)
+
+// msmMultiplier is the denominator of discount values
+const msmMultiplier = 1000
+
+// g1MSMDiscountTable is discount table for G1MSM where the value at index i is the discount for i + 1 pairs.
+var g1MSMDiscountTable = [128]uint64{
+	1000, 949, 848, 797, 764, 750, 738, 728, 719, 712, 705, 698, 692, 687, 682, 677,
+	673, 669, 665, 661, 658, 654, 651, 648, 645, 642, 640, 637, 635, 632, 630, 627,
+	625, 623, 621, 619, 617, 615, 613, 611, 609, 608, 606, 604, 603, 601, 599, 598,
+	596, 595, 593, 592, 591, 589, 588, 586, 585, 584, 582, 581, 580, 579, 577, 576,
+	575, 574, 573, 572, 570, 569, 568, 567, 566, 565, 564, 563, 562, 561, 560, 559,
+	558, 557, 556, 555, 554, 553, 552, 551, 550, 549, 548, 547, 547, 546, 545, 544,
+	543, 542, 541, 540, 540, 539, 538, 537, 536, 536, 535, 534, 533, 532, 532, 531,
+	530, 529, 528, 528, 527, 526, 525, 525, 524, 523, 522, 522, 521, 520, 520, 519,
+}
+
+// g2MSMDiscountTable is discount table for G2MSM where the value at index i is the discount for i + 1 pairs.
+var g2MSMDiscountTable = [128]uint64{
+	1000, 1000, 923, 884, 855, 832, 812, 796, 782, 770, 759, 749, 740, 732, 724, 717,
+	711, 704, 699, 693, 688, 683, 679, 674, 670, 666, 663, 659, 655, 652, 649, 646,
+	643, 640, 637, 634, 632, 629, 627, 624, 622, 620, 618, 615, 613, 611, 609, 607,
+	606, 604, 602, 600, 598, 597, 595, 593, 592, 590, 589, 587, 586, 584, 583, 582,
+	580, 579, 578, 576, 575, 574, 573, 571, 570, 569, 568, 567, 566, 565, 563, 562,
+	561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 552, 551, 550, 549, 548, 547,
+	546, 545, 545, 544, 543, 542, 541, 541, 540, 539, 538, 537, 537, 536, 535, 535,
+	534, 533, 532, 532, 531, 530, 530, 529, 528, 528, 527, 526, 526, 525, 524, 524,
+}
+
+// msmGas calculates the gas cost of multi scalar multiplication with k pairs
+// as k * mulGas * discount(k) / 1000. Discount stays at its last value for k > 128.
+func msmGas(k int, mulGas uint64, discountTable []uint64) uint64 {
+	if k == 0 {
+		return 0
+	}
+	discount := discountTable[len(discountTable)-1]
+	if k <= len(discountTable) {
+		discount = discountTable[k-1]
+	}
+	return (uint64(k) * mulGas * discount) / msmMultiplier
+}
@@ -0,0 +1,375 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// Points are encoded as uniform byte strings following Elligator Squared.
+// https://eprint.iacr.org/2014/043
+//
+// Decoding a byte string is same as hashing to curve after hashing to field, that is,
+// two field elements u0 and u1 are mapped to the isogenous curve with simplified SWU,
+// summed, mapped to the curve with the isogeny and finally the cofactor is cleared.
+// Since any byte string decodes to a valid point, random strings and encoded points
+// can not be told apart.
+//
+// To encode a point p, target point s on isogenous curve is sampled uniformly among points
+// which decode to p. Then u0 is chosen at random and u1 is drawn among preimages of s - SWU(u0)
+// which are at most four. Iteration is repeated with probability proportional to the
+// number of missing preimages so that (u0, u1) is uniform among pairs which are mapped to s.
+// Field elements are serialized into 64 bytes after adding a random multiple of the modulus.
+
+// swuMaxPreimages is the upper bound for number of preimages of a point under simplified SWU map.
+const swuMaxPreimages = 4
+
+// uniformMultiplierBound is the exclusive upper bound of k such that u + k * p < 2^512.
+var uniformMultiplierBound = new(big.Int).Add(new(big.Int).Div(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 512), pBig), pBig), big.NewInt(1))
+
+// uniformBytes serializes a field element into 64 bytes indistinguishable from random.
+func uniformBytes(u *fe) ([]byte, error) {
+	k, err := rand.Int(rand.Reader, uniformMultiplierBound)
+	if err != nil {
+		return nil, err
+	}
+	k.Mul(k, pBig)
+	k.Add(k, toBig(u))
+	out := make([]byte, 64)
+	b := k.Bytes()
+	copy(out[64-len(b):], b)
+	return out, nil
+}
+
+// randPreimageIndex returns a uniform index of preimages that are at most swuMaxPreimages.
+func randPreimageIndex() (int, error) {
+	b := make([]byte, 1)
+	if _, err := rand.Read(b); err != nil {
+		return 0, err
+	}
+	return int(b[0] % swuMaxPreimages), nil
+}
+
+// ToUniformBytes encodes a G1 point into 128 bytes which are indistinguishable from random bytes.
+// Encoding is randomized and FromUniformBytes recovers the point.
+// Point is expected to be in correct subgroup.
+func (g *G1) ToUniformBytes(p *PointG1) ([]byte, error) {
+	if !g.InCorrectSubgroup(p) {
+		return nil, ErrNotInSubgroup
+	}
+	// q = h_eff^-1 * p so that clearing cofactor of the decoded point gives p back
+	q := g.Affine(g.MulScalarBig(g.New(), p, cofactorEFFG1Inv))
+	s := g.Zero()
+	if !g.IsZero(q) {
+		x, y, ok := isogenyPreimageG1(&q[0], &q[1])
+		if !ok {
+			return nil, errors.New("isogeny preimage is not found")
+		}
+		s = &PointG1{*x, *y, *new(fe).one()}
+	}
+	// randomize the component of s out of the prime order subgroup
+	t, err := g.randIsoPoint()
+	if err != nil {
+		return nil, err
+	}
+	g.isoMulBig(t, t, qBig)
+	g.Affine(g.isoAdd(s, s, t))
+	u0, u1, err := g.isoSplit(s)
+	if err != nil {
+		return nil, err
+	}
+	out0, err := uniformBytes(u0)
+	if err != nil {
+		return nil, err
+	}
+	out1, err := uniformBytes(u1)
+	if err != nil {
+		return nil, err
+	}
+	return append(out0, out1...), nil
+}
+
+// FromUniformBytes decodes 128 bytes into a G1 point.
+// Any input decodes to a point in correct subgroup.
+func (g *G1) FromUniformBytes(in []byte) (*PointG1, error) {
+	if len(in) != 128 {
+		return nil, errors.New("input string must be equal to 128 bytes")
+	}
+	u, err := fieldElementsFromBytes(in, 2)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToCurveRO(u[0], u[1]), nil
+}
+
+// isoSplit returns u0 and u1 such that SWU(u0) + SWU(u1) = s where s is a point of the isogenous curve in affine form.
+func (g *G1) isoSplit(s *PointG1) (*fe, *fe, error) {
+	for {
+		u0, err := new(fe).rand(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		x, y := swuMapG1(u0)
+		r := &PointG1{*x, *y, *new(fe).one()}
+		g.Neg(r, r)
+		if g.IsZero(g.isoAdd(r, r, s)) {
+			continue
+		}
+		g.Affine(r)
+		preimages := swuInverseG1(&r[0], &r[1])
+		j, err := randPreimageIndex()
+		if err != nil {
+			return nil, nil, err
+		}
+		if j < len(preimages) {
+			return u0, preimages[j], nil
+		}
+	}
+}
+
+// randIsoPoint returns a random point of the isogenous curve.
+func (g *G1) randIsoPoint() (*PointG1, error) {
+	params := swuParamsForG1
+	for {
+		x, err := new(fe).rand(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		y := new(fe)
+		square(y, x)
+		add(y, y, params.a)
+		mul(y, y, x)
+		add(y, y, params.b)
+		if !sqrt(y, y) {
+			continue
+		}
+		j, err := randPreimageIndex()
+		if err != nil {
+			return nil, err
+		}
+		if j&1 == 1 {
+			neg(y, y)
+		}
+		return &PointG1{*x, *y, *new(fe).one()}, nil
+	}
+}
+
+// isoAdd adds two points of the isogenous curve.
+// Addition formulas do not depend on the curve coefficient a, only doubling does.
+func (g *G1) isoAdd(r, p1, p2 *PointG1) *PointG1 {
+	if !g.IsZero(p1) && g.Equal(p1, p2) {
+		return g.isoDouble(r, p1)
+	}
+	return g.Add(r, p1, p2)
+}
+
+// isoDouble doubles a point of the isogenous curve.
+func (g *G1) isoDouble(r, p *PointG1) *PointG1 {
+	// http://www.hyperelliptic.org/EFD/g1p/auto-shortw-jacobian.html#doubling-dbl-2007-bl
+	if g.IsZero(p) {
+		return r.Zero()
+	}
+	t := g.t
+	square(t[0], &p[0])               // xx = x^2
+	square(t[1], &p[1])               // yy = y^2
+	square(t[2], t[1])                // yyyy = yy^2
+	square(t[3], &p[2])               // zz = z^2
+	add(t[4], &p[0], t[1])            // x + yy
+	square(t[4], t[4])                // (x + yy)^2
+	subAssign(t[4], t[0])             // (x + yy)^2 - xx
+	subAssign(t[4], t[2])             // (x + yy)^2 - xx - yyyy
+	doubleAssign(t[4])                // s = 2((x + yy)^2 - xx - yyyy)
+	square(t[5], t[3])                // zz^2
+	mul(t[5], t[5], swuParamsForG1.a) // a * zz^2
+	double(t[6], t[0])                // 2xx
+	addAssign(t[6], t[0])             // 3xx
+	addAssign(t[6], t[5])             // m = 3xx + a * zz^2
+	add(t[7], &p[1], &p[2])           // y + z
+	square(t[7], t[7])                // (y + z)^2
+	subAssign(t[7], t[1])             // (y + z)^2 - yy
+	sub(&r[2], t[7], t[3])            // z3 = (y + z)^2 - yy - zz
+	square(t[7], t[6])                // m^2
+	double(t[8], t[4])                // 2s
+	sub(&r[0], t[7], t[8])            // x3 = m^2 - 2s
+	sub(t[4], t[4], &r[0])            // s - x3
+	mul(t[4], t[4], t[6])             // m * (s - x3)
+	doubleAssign(t[2])                //
+	doubleAssign(t[2])                //
+	doubleAssign(t[2])                // 8yyyy
+	sub(&r[1], t[4], t[2])            // y3 = m * (s - x3) - 8yyyy
+	return r
+}
+
+// isoMulBig multiplies a point of the isogenous curve by given scalar.
+func (g *G1) isoMulBig(r, p *PointG1, e *big.Int) *PointG1 {
+	q, n := g.New(), g.New().Set(p)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		g.isoDouble(q, q)
+		if e.Bit(i) == 1 {
+			g.isoAdd(q, q, n)
+		}
+	}
+	return r.Set(q)
+}
+
+// ToUniformBytes encodes a G2 point into 256 bytes which are indistinguishable from random bytes.
+// Encoding is randomized and FromUniformBytes recovers the point.
+// Point is expected to be in correct subgroup.
+func (g *G2) ToUniformBytes(p *PointG2) ([]byte, error) {
+	if !g.InCorrectSubgroup(p) {
+		return nil, ErrNotInSubgroup
+	}
+	// q = h_eff^-1 * p so that clearing cofactor of the decoded point gives p back
+	q := g.Affine(g.MulScalarBig(g.New(), p, cofactorEFFG2Inv))
+	s := g.Zero()
+	if !g.IsZero(q) {
+		x, y, ok := isogenyPreimageG2(g.f, &q[0], &q[1])
+		if !ok {
+			return nil, errors.New("isogeny preimage is not found")
+		}
+		s = &PointG2{*x, *y, *new(fe2).one()}
+	}
+	// randomize the component of s out of the prime order subgroup
+	t, err := g.randIsoPoint()
+	if err != nil {
+		return nil, err
+	}
+	g.isoMulBig(t, t, qBig)
+	g.Affine(g.isoAdd(s, s, t))
+	u0, u1, err := g.isoSplit(s)
+	if err != nil {
+		return nil, err
+	}
+	out := []byte{}
+	for _, u := range []*fe{&u0[0], &u0[1], &u1[0], &u1[1]} {
+		b, err := uniformBytes(u)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+// FromUniformBytes decodes 256 bytes into a G2 point.
+// Any input decodes to a point in correct subgroup.
+func (g *G2) FromUniformBytes(in []byte) (*PointG2, error) {
+	if len(in) != 256 {
+		return nil, errors.New("input string must be equal to 256 bytes")
+	}
+	u, err := fieldElementsFromBytes(in, 4)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToCurveRO(&fe2{*u[0], *u[1]}, &fe2{*u[2], *u[3]}), nil
+}
+
+// isoSplit returns u0 and u1 such that SWU(u0) + SWU(u1) = s where s is a point of the isogenous curve in affine form.
+func (g *G2) isoSplit(s *PointG2) (*fe2, *fe2, error) {
+	for {
+		u0, err := new(fe2).rand(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		x, y := swuMapG2(g.f, u0)
+		r := &PointG2{*x, *y, *new(fe2).one()}
+		g.Neg(r, r)
+		if g.IsZero(g.isoAdd(r, r, s)) {
+			continue
+		}
+		g.Affine(r)
+		preimages := swuInverseG2(g.f, &r[0], &r[1])
+		j, err := randPreimageIndex()
+		if err != nil {
+			return nil, nil, err
+		}
+		if j < len(preimages) {
+			return u0, preimages[j], nil
+		}
+	}
+}
+
+// randIsoPoint returns a random point of the isogenous curve.
+func (g *G2) randIsoPoint() (*PointG2, error) {
+	params := swuParamsForG2
+	for {
+		x, err := new(fe2).rand(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		y := new(fe2)
+		g.f.square(y, x)
+		fp2Add(y, y, params.a)
+		g.f.mul(y, y, x)
+		fp2Add(y, y, params.b)
+		if !g.f.sqrt(y, y) {
+			continue
+		}
+		j, err := randPreimageIndex()
+		if err != nil {
+			return nil, err
+		}
+		if j&1 == 1 {
+			fp2Neg(y, y)
+		}
+		return &PointG2{*x, *y, *new(fe2).one()}, nil
+	}
+}
+
+// isoAdd adds two points of the isogenous curve.
+// Addition formulas do not depend on the curve coefficient a, only doubling does.
+func (g *G2) isoAdd(r, p1, p2 *PointG2) *PointG2 {
+	if !g.IsZero(p1) && g.Equal(p1, p2) {
+		return g.isoDouble(r, p1)
+	}
+	return g.Add(r, p1, p2)
+}
+
+// isoDouble doubles a point of the isogenous curve.
+func (g *G2) isoDouble(r, p *PointG2) *PointG2 {
+	// http://www.hyperelliptic.org/EFD/g1p/auto-shortw-jacobian.html#doubling-dbl-2007-bl
+	if g.IsZero(p) {
+		return r.Zero()
+	}
+	t := g.t
+	g.f.square(t[0], &p[0])               // xx = x^2
+	g.f.square(t[1], &p[1])               // yy = y^2
+	g.f.square(t[2], t[1])                // yyyy = yy^2
+	g.f.square(t[3], &p[2])               // zz = z^2
+	fp2Add(t[4], &p[0], t[1])             // x + yy
+	g.f.square(t[4], t[4])                // (x + yy)^2
+	fp2Sub(t[4], t[4], t[0])              // (x + yy)^2 - xx
+	fp2Sub(t[4], t[4], t[2])              // (x + yy)^2 - xx - yyyy
+	fp2Double(t[4], t[4])                 // s = 2((x + yy)^2 - xx - yyyy)
+	g.f.square(t[5], t[3])                // zz^2
+	g.f.mul(t[5], t[5], swuParamsForG2.a) // a * zz^2
+	fp2Double(t[6], t[0])                 // 2xx
+	fp2Add(t[6], t[6], t[0])              // 3xx
+	fp2Add(t[6], t[6], t[5])              // m = 3xx + a * zz^2
+	fp2Add(t[7], &p[1], &p[2])            // y + z
+	g.f.square(t[7], t[7])                // (y + z)^2
+	fp2Sub(t[7], t[7], t[1])              // (y + z)^2 - yy
+	fp2Sub(&r[2], t[7], t[3])             // z3 = (y + z)^2 - yy - zz
+	g.f.square(t[7], t[6])                // m^2
+	fp2Double(t[8], t[4])                 // 2s
+	fp2Sub(&r[0], t[7], t[8])             // x3 = m^2 - 2s
+	fp2Sub(t[4], t[4], &r[0])             // s - x3
+	g.f.mul(t[4], t[4], t[6])             // m * (s - x3)
+	fp2Double(t[2], t[2])                 //
+	fp2Double(t[2], t[2])                 //
+	fp2Double(t[2], t[2])                 // 8yyyy
+	fp2Sub(&r[1], t[4], t[2])             // y3 = m * (s - x3) - 8yyyy
+	return r
+}
+
+// isoMulBig multiplies a point of the isogenous curve by given scalar.
+func (g *G2) isoMulBig(r, p *PointG2, e *big.Int) *PointG2 {
+	q, n := g.New(), g.New().Set(p)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		g.isoDouble(q, q)
+		if e.Bit(i) == 1 {
+			g.isoAdd(q, q, n)
+		}
+	}
+	return r.Set(q)
+}
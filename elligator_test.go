@@ -0,0 +1,227 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math"
+	"testing"
+)
+
+func TestSWUInverse(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe).rand(rand.Reader)
+		x, y := swuMapG1(u)
+		preimages := swuInverseG1(x, y)
+		if len(preimages) == 0 || len(preimages) > swuMaxPreimages {
+			t.Fatal("bad number of preimages", len(preimages))
+		}
+		found := false
+		for _, v := range preimages {
+			x0, y0 := swuMapG1(v)
+			if !x0.equal(x) || !y0.equal(y) {
+				t.Fatal("bad preimage")
+			}
+			found = found || v.equal(u)
+		}
+		if !found {
+			t.Fatal("preimage is not found")
+		}
+	}
+	e := newFp2()
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe2).rand(rand.Reader)
+		x, y := swuMapG2(e, u)
+		preimages := swuInverseG2(e, x, y)
+		if len(preimages) == 0 || len(preimages) > swuMaxPreimages {
+			t.Fatal("bad number of preimages", len(preimages))
+		}
+		found := false
+		for _, v := range preimages {
+			x0, y0 := swuMapG2(e, v)
+			if !x0.equal(x) || !y0.equal(y) {
+				t.Fatal("bad preimage")
+			}
+			found = found || v.equal(u)
+		}
+		if !found {
+			t.Fatal("preimage is not found")
+		}
+	}
+	// exceptional input
+	x, y := swuMapG1(new(fe))
+	preimages := swuInverseG1(x, y)
+	if len(preimages) == 0 || !preimages[0].isZero() {
+		t.Fatal("zero preimage is not found")
+	}
+}
+
+func TestIsogenyPreimage(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe).rand(rand.Reader)
+		x, y := swuMapG1(u)
+		isogenyMapG1(x, y)
+		x0, y0, ok := isogenyPreimageG1(x, y)
+		if !ok {
+			t.Fatal("isogeny preimage is not found")
+		}
+		isogenyMapG1(x0, y0)
+		if !x0.equal(x) || !y0.equal(y) {
+			t.Fatal("bad isogeny preimage")
+		}
+	}
+	e := newFp2()
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe2).rand(rand.Reader)
+		x, y := swuMapG2(e, u)
+		isogenyMapG2(e, x, y)
+		x0, y0, ok := isogenyPreimageG2(e, x, y)
+		if !ok {
+			t.Fatal("isogeny preimage is not found")
+		}
+		isogenyMapG2(e, x0, y0)
+		if !x0.equal(x) || !y0.equal(y) {
+			t.Fatal("bad isogeny preimage")
+		}
+	}
+}
+
+func TestG1UniformBytes(t *testing.T) {
+	g := NewG1()
+	for i := 0; i < fuz; i++ {
+		p := g.randCorrect()
+		in, err := g.ToUniformBytes(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(in) != 128 {
+			t.Fatal("bad encoding length")
+		}
+		q, err := g.FromUniformBytes(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(p, q) {
+			t.Fatal("uniform encoding roundtrip fails")
+		}
+	}
+	in, err := g.ToUniformBytes(g.Zero())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q, _ := g.FromUniformBytes(in); !g.IsZero(q) {
+		t.Fatal("uniform encoding roundtrip fails for zero")
+	}
+	if _, err := g.ToUniformBytes(g.rand()); err == nil {
+		t.Fatal("point out of subgroup must be rejected")
+	}
+	// any input decodes to a valid point
+	for i := 0; i < fuz; i++ {
+		in := make([]byte, 128)
+		_, _ = rand.Read(in)
+		p, err := g.FromUniformBytes(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.IsOnCurve(p) || !g.InCorrectSubgroup(p) {
+			t.Fatal("bad decoding")
+		}
+	}
+	if _, err := g.FromUniformBytes(make([]byte, 127)); err == nil {
+		t.Fatal("bad input length must be rejected")
+	}
+}
+
+func TestG2UniformBytes(t *testing.T) {
+	g := NewG2()
+	for i := 0; i < fuz; i++ {
+		p := g.randCorrect()
+		in, err := g.ToUniformBytes(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(in) != 256 {
+			t.Fatal("bad encoding length")
+		}
+		q, err := g.FromUniformBytes(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(p, q) {
+			t.Fatal("uniform encoding roundtrip fails")
+		}
+	}
+	in, err := g.ToUniformBytes(g.Zero())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q, _ := g.FromUniformBytes(in); !g.IsZero(q) {
+		t.Fatal("uniform encoding roundtrip fails for zero")
+	}
+	if _, err := g.ToUniformBytes(g.rand()); err == nil {
+		t.Fatal("point out of subgroup must be rejected")
+	}
+	for i := 0; i < fuz; i++ {
+		in := make([]byte, 256)
+		_, _ = rand.Read(in)
+		p, err := g.FromUniformBytes(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.IsOnCurve(p) || !g.InCorrectSubgroup(p) {
+			t.Fatal("bad decoding")
+		}
+	}
+	if _, err := g.FromUniformBytes(make([]byte, 255)); err == nil {
+		t.Fatal("bad input length must be rejected")
+	}
+}
+
+// checkUniformBits checks that each bit position of encodings is set
+// with frequency close to one half, within six standard deviations.
+func checkUniformBits(t *testing.T, encodings [][]byte) {
+	n := float64(len(encodings))
+	limit := 6 * math.Sqrt(n) / 2
+	for i := 0; i < 8*len(encodings[0]); i++ {
+		ones := 0.0
+		for _, in := range encodings {
+			ones += float64((in[i/8] >> (7 - uint(i%8))) & 1)
+		}
+		if math.Abs(ones-n/2) > limit {
+			t.Fatal("bit is biased", i, ones, n)
+		}
+	}
+}
+
+func TestG1UniformBytesDistribution(t *testing.T) {
+	g := NewG1()
+	// the same point is encoded repeatedly
+	p := g.randCorrect()
+	encodings := make([][]byte, 100)
+	for i := range encodings {
+		in, err := g.ToUniformBytes(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < i; j++ {
+			if bytes.Equal(in, encodings[j]) {
+				t.Fatal("encoding must be randomized")
+			}
+		}
+		encodings[i] = in
+	}
+	checkUniformBits(t, encodings)
+}
+
+func TestG2UniformBytesDistribution(t *testing.T) {
+	g := NewG2()
+	p := g.randCorrect()
+	encodings := make([][]byte, 100)
+	for i := range encodings {
+		in, err := g.ToUniformBytes(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		encodings[i] = in
+	}
+	checkUniformBits(t, encodings)
+}
@@ -228,6 +228,49 @@ func (e *fp12) cyclotomicExp(c, a *fe12, s *big.Int) {
 	c.set(z)
 }
 
+// cyclotomicExpX raises an element of cyclotomic subgroup by x = -15132376222941642752.
+// Result must not be assigned to the input.
+func (e *fp12) cyclotomicExpX(c, a *fe12) {
+	c.set(a)
+	e.cyclotomicSquare(c) // (a ^ 2)
+
+	// (a ^ (2 + 1)) ^ (2 ^ 2) = a ^ 12
+	e.mulAssign(c, a)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+
+	// (a ^ (12 + 1)) ^ (2 ^ 3) = a ^ 104
+	e.mulAssign(c, a)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+
+	// (a ^ (104 + 1)) ^ (2 ^ 9) = a ^ 53760
+	e.mulAssign(c, a)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	e.cyclotomicSquare(c)
+	// (a ^ (53760 + 1)) ^ (2 ^ 32) = a ^ 230901736800256
+	e.mulAssign(c, a)
+	for i := 0; i < 32; i++ {
+		e.cyclotomicSquare(c)
+	}
+
+	// (a ^ (230901736800256 + 1)) ^ (2 ^ 16) = a ^ 15132376222941642752
+	e.mulAssign(c, a)
+	for i := 0; i < 16; i++ {
+		e.cyclotomicSquare(c)
+	}
+	// invert chain result since x is negative
+	fp12Conjugate(c, c)
+}
+
 func (e *fp12) cyclotomicSquare(a *fe12) {
 	t := e.t2
 	// Guide to Pairing Based Cryptography
@@ -232,6 +232,62 @@ func (e *fp2) sqrt(c, a *fe2) bool {
 	return alpha.equal(u)
 }
 
+// sqrtBatch calculates square roots of given elements and assigns them to c.
+// For each element it reports whether the element is a quadratic residue.
+// For a = a0 + a1*u square root is x0 + x1*u where
+// x0 = sqrt((a0 ± sqrt(a0^2 + a1^2)) / 2) and x1 = a1 / (2 * x0)
+// so that only square roots in base field are required and inversions are shared.
+func (e *fp2) sqrtBatch(c, a []fe2) []bool {
+	ok := make([]bool, len(a))
+	x0 := make([]fe, len(a))
+	t, alpha := new(fe), new(fe)
+	for i := range a {
+		if a[i][1].isZero() {
+			// a0 or -a0 is a square in base field since p = 3 mod 4
+			if sqrt(t, &a[i][0]) {
+				c[i][0].set(t)
+				c[i][1].zero()
+			} else {
+				neg(t, &a[i][0])
+				sqrt(&c[i][1], t)
+				c[i][0].zero()
+			}
+			ok[i] = true
+			continue
+		}
+		// alpha = sqrt(a0^2 + a1^2), a is a square iff its norm is a square
+		square(t, &a[i][0])
+		square(alpha, &a[i][1])
+		add(alpha, alpha, t)
+		if !sqrt(alpha, alpha) {
+			continue
+		}
+		// x0 = sqrt((a0 + alpha) / 2) or x0 = sqrt((a0 - alpha) / 2)
+		add(t, &a[i][0], alpha)
+		mul(t, t, twoInv)
+		if !sqrt(&x0[i], t) {
+			sub(t, &a[i][0], alpha)
+			mul(t, t, twoInv)
+			if !sqrt(&x0[i], t) {
+				x0[i].zero()
+				continue
+			}
+		}
+		c[i][0].set(&x0[i])
+		ok[i] = true
+	}
+	// x0 is non zero if a1 is non zero
+	inverseBatch(x0)
+	for i := range a {
+		if ok[i] && !a[i][1].isZero() {
+			// x1 = a1 / (2 * x0)
+			mul(&c[i][1], &a[i][1], &x0[i])
+			mul(&c[i][1], &c[i][1], twoInv)
+		}
+	}
+	return ok
+}
+
 func (e *fp2) isQuadraticNonResidue(a *fe2) bool {
 	c0, c1 := new(fe), new(fe)
 	square(c0, &a[0])
@@ -1017,6 +1017,36 @@ func TestFp2SquareRoot(t *testing.T) {
 	}
 }
 
+func TestFp2SquareRootBatch(t *testing.T) {
+	e := newFp2()
+	n := 64
+	a := make([]fe2, n)
+	for i := 0; i < n; i++ {
+		r, _ := new(fe2).rand(rand.Reader)
+		a[i].set(r)
+	}
+	// elements in base field
+	a[0][1].zero()
+	a[1][1].zero()
+	neg(&a[1][0], &a[0][0])
+	a[2].zero()
+	a[3].set(nonResidue2)
+	roots := make([]fe2, n)
+	ok := e.sqrtBatch(roots, a)
+	for i := 0; i < n; i++ {
+		r := new(fe2)
+		if d := e.sqrt(r, &a[i]); d != ok[i] {
+			t.Fatal("sqrt decision failed", i)
+		}
+		if ok[i] {
+			e.square(r, &roots[i])
+			if !r.equal(&a[i]) {
+				t.Fatal("sqrt failed", i)
+			}
+		}
+	}
+}
+
 func TestFp2NonResidue(t *testing.T) {
 	f := newFp2()
 	if !f.isQuadraticNonResidue(nonResidue2) {
@@ -2,7 +2,7 @@ package bls12381
 
 import (
 	"errors"
-	"math"
+	"fmt"
 	"math/big"
 )
 
@@ -64,42 +64,37 @@ func (g *G1) Q() *big.Int {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G1) FromUncompressed(uncompressed []byte) (*PointG1, error) {
+	return g.DecodeUncompressed(uncompressed, DecodeStrict)
+}
+
+// DecodeUncompressed decodes a point in uncompressed form as FromUncompressed does
+// and applies validations according to the given policy.
+func (g *G1) DecodeUncompressed(uncompressed []byte, policy DecodePolicy) (*PointG1, error) {
 	if len(uncompressed) != 2*fpByteSize {
 		return nil, errors.New("input string length must be equal to 96 bytes")
 	}
 	var in [2 * fpByteSize]byte
 	copy(in[:], uncompressed[:2*fpByteSize])
 	if in[0]&(1<<7) != 0 {
-		return nil, errors.New("compression flag must be zero")
+		return nil, fmt.Errorf("%w: compression flag must be zero", ErrBadFlags)
 	}
 	if in[0]&(1<<5) != 0 {
-		return nil, errors.New("sort flag must be zero")
+		return nil, fmt.Errorf("%w: sort flag must be zero", ErrBadFlags)
 	}
 	if in[0]&(1<<6) != 0 {
-		for i, v := range in {
-			if (i == 0 && v != 0x40) || (i != 0 && v != 0x00) {
-				return nil, errors.New("input string must be zero when infinity flag is set")
-			}
+		if !isZeroBytes(in[:], 0x1f) {
+			return nil, fmt.Errorf("%w: input string must be zero when infinity flag is set", ErrNonCanonical)
 		}
 		return g.Zero(), nil
 	}
 	in[0] &= 0x1f
-	x, err := fromBytes(in[:fpByteSize])
+	p, err := g.fromBytesUnchecked(in[:])
 	if err != nil {
 		return nil, err
 	}
-	y, err := fromBytes(in[fpByteSize:])
-	if err != nil {
+	if err := g.checkPoint(p, policy); err != nil {
 		return nil, err
 	}
-	z := new(fe).one()
-	p := &PointG1{*x, *y, *z}
-	if !g.IsOnCurve(p) {
-		return nil, errors.New("point is not on curve")
-	}
-	if !g.InCorrectSubgroup(p) {
-		return nil, errors.New("point is not on correct subgroup")
-	}
 	return p, nil
 }
 
@@ -113,7 +108,7 @@ func (g *G1) ToUncompressed(p *PointG1) []byte {
 		out[0] |= 1 << 6
 		return out
 	}
-	g.Affine(p)
+	p = g.affine(g.New(), p)
 	copy(out[:fpByteSize], toBytes(&p[0]))
 	copy(out[fpByteSize:], toBytes(&p[1]))
 	return out
@@ -124,55 +119,75 @@ func (g *G1) ToUncompressed(p *PointG1) []byte {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G1) FromCompressed(compressed []byte) (*PointG1, error) {
-	if len(compressed) != fpByteSize {
-		return nil, errors.New("input string length must be equal to 48 bytes")
-	}
-	var in [fpByteSize]byte
-	copy(in[:], compressed[:])
-	if in[0]&(1<<7) == 0 {
-		return nil, errors.New("compression flag must be set")
-	}
-	if in[0]&(1<<6) != 0 {
-		// in[0] == (1 << 6) + (1 << 7)
-		for i, v := range in {
-			if (i == 0 && v != 0xc0) || (i != 0 && v != 0x00) {
-				return nil, errors.New("input string must be zero when infinity flag is set")
-			}
-		}
-		return g.Zero(), nil
-	}
-	a := in[0]&(1<<5) != 0
-	in[0] &= 0x1f
-	x, err := fromBytes(in[:])
+	return g.DecodeCompressed(compressed, DecodeStrict)
+}
+
+// DecodeCompressed decodes a point in compressed form as FromCompressed does
+// and applies validations according to the given policy.
+// Decompressed points are always on curve so only subgroup check is subject to the policy.
+func (g *G1) DecodeCompressed(compressed []byte, policy DecodePolicy) (*PointG1, error) {
+	x, a, err := g.parseCompressed(compressed)
 	if err != nil {
 		return nil, err
 	}
+	if x == nil {
+		return g.Zero(), nil
+	}
 	// solve curve equation
 	y := &fe{}
 	square(y, x)
 	mul(y, y, x)
 	add(y, y, b)
 	if ok := sqrt(y, y); !ok {
-		return nil, errors.New("point is not on curve")
+		return nil, ErrNotOnCurve
 	}
 	if y.signBE() == a {
 		neg(y, y)
 	}
 	z := new(fe).one()
 	p := &PointG1{*x, *y, *z}
-	if !g.InCorrectSubgroup(p) {
-		return nil, errors.New("point is not on correct subgroup")
+	if policy.checkSubgroup() && !g.InCorrectSubgroup(p) {
+		return nil, ErrNotInSubgroup
 	}
 	return p, nil
 }
 
+// parseCompressed checks flags of a compressed point and returns x coordinate along with the sort flag.
+// Returned x coordinate is nil if the input is point at infinity.
+func (g *G1) parseCompressed(compressed []byte) (*fe, bool, error) {
+	if len(compressed) != fpByteSize {
+		return nil, false, errors.New("input string length must be equal to 48 bytes")
+	}
+	var in [fpByteSize]byte
+	copy(in[:], compressed[:])
+	if in[0]&(1<<7) == 0 {
+		return nil, false, fmt.Errorf("%w: compression flag must be set", ErrBadFlags)
+	}
+	if in[0]&(1<<6) != 0 {
+		if in[0]&(1<<5) != 0 {
+			return nil, false, fmt.Errorf("%w: sort flag must be zero when infinity flag is set", ErrBadFlags)
+		}
+		if !isZeroBytes(in[:], 0x1f) {
+			return nil, false, fmt.Errorf("%w: input string must be zero when infinity flag is set", ErrNonCanonical)
+		}
+		return nil, false, nil
+	}
+	a := in[0]&(1<<5) != 0
+	in[0] &= 0x1f
+	x, err := fromBytes(in[:])
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrNonCanonical, err)
+	}
+	return x, a, nil
+}
+
 // ToCompressed given a G1 point returns bytes in compressed form of the point.
 // Serialization rules are in line with zcash library. See below for details.
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G1) ToCompressed(p *PointG1) []byte {
 	out := make([]byte, fpByteSize)
-	g.Affine(p)
+	p = g.affine(g.New(), p)
 	if g.IsZero(p) {
 		out[0] |= 1 << 6
 	} else {
@@ -185,42 +200,55 @@ func (g *G1) ToCompressed(p *PointG1) []byte {
 	return out
 }
 
+// fromBytesUnchecked parses x and y coordinates without applying any point validation.
 func (g *G1) fromBytesUnchecked(in []byte) (*PointG1, error) {
 	p0, err := fromBytes(in[:fpByteSize])
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrNonCanonical, err)
 	}
 	p1, err := fromBytes(in[fpByteSize:])
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrNonCanonical, err)
 	}
 	p2 := new(fe).one()
 	return &PointG1{*p0, *p1, *p2}, nil
 }
 
+// checkPoint applies on curve and subgroup checks according to the given policy.
+func (g *G1) checkPoint(p *PointG1, policy DecodePolicy) error {
+	if policy.checkOnCurve() && !g.IsOnCurve(p) {
+		return ErrNotOnCurve
+	}
+	if policy.checkSubgroup() && !g.InCorrectSubgroup(p) {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
 // FromBytes constructs a new point given uncompressed byte input.
 // Input string is expected to be equal to 96 bytes and concatenation of x and y cooridanates.
 // (0, 0) is considered as infinity.
+// FromBytes checks that the point is on curve but does not apply subgroup check.
 func (g *G1) FromBytes(in []byte) (*PointG1, error) {
+	return g.DecodeBytes(in, DecodeNoSubgroupCheck)
+}
+
+// DecodeBytes decodes a point in raw (x, y) form as FromBytes does
+// and applies validations according to the given policy.
+func (g *G1) DecodeBytes(in []byte, policy DecodePolicy) (*PointG1, error) {
 	if len(in) != 2*fpByteSize {
 		return nil, errors.New("input string length must be equal to 96 bytes")
 	}
-	p0, err := fromBytes(in[:fpByteSize])
-	if err != nil {
-		return nil, err
-	}
-	p1, err := fromBytes(in[fpByteSize:])
+	p, err := g.fromBytesUnchecked(in)
 	if err != nil {
 		return nil, err
 	}
 	// check if given input points to infinity
-	if p0.isZero() && p1.isZero() {
+	if p[0].isZero() && p[1].isZero() {
 		return g.Zero(), nil
 	}
-	p2 := new(fe).one()
-	p := &PointG1{*p0, *p1, *p2}
-	if !g.IsOnCurve(p) {
-		return nil, errors.New("point is not on curve")
+	if err := g.checkPoint(p, policy); err != nil {
+		return nil, err
 	}
 	return p, nil
 }
@@ -232,7 +260,7 @@ func (g *G1) ToBytes(p *PointG1) []byte {
 	if g.IsZero(p) {
 		return out
 	}
-	g.Affine(p)
+	p = g.affine(g.New(), p)
 	copy(out[:fpByteSize], toBytes(&p[0]))
 	copy(out[fpByteSize:], toBytes(&p[1]))
 	return out
@@ -524,6 +552,35 @@ func (g *G1) MulScalar(r, p *PointG1, e *Fr) *PointG1 {
 	return g.glvMulFr(r, p, e)
 }
 
+// MulScalarCT is constant time version of MulScalar for secret scalars such as private keys.
+// It uses fixed window method with signed digits where each lookup scans the whole table
+// and additions handle zero and equal inputs without branching.
+func (g *G1) MulScalarCT(r, p *PointG1, e *Fr) *PointG1 {
+	var table [ctMulRowSize]PointG1
+	table[0].Set(p)
+	g.doubleCT(&table[1], p)
+	for d := 2; d < ctMulRowSize; d++ {
+		g.addCT(&table[d], &table[d-1], p)
+	}
+	digits, signs := ctSignedDigits(e, ctMulWindowSize, ctMulWindows)
+	acc, sum, q := g.New(), g.New(), g.New()
+	y := new(fe)
+	for j := ctMulWindows - 1; j >= 0; j-- {
+		for i := 0; i < ctMulWindowSize; i++ {
+			g.doubleCT(acc, acc)
+		}
+		q.Set(&table[0])
+		for d := 1; d < ctMulRowSize; d++ {
+			q.cmov(&table[d], ctEqualUint64(digits[j], uint64(d+1)))
+		}
+		ctNeg(y, &q[1])
+		q[1].cmov(y, signs[j])
+		g.addCT(sum, acc, q)
+		acc.cmov(sum, ctEqualUint64(digits[j], 0)^1)
+	}
+	return r.Set(acc)
+}
+
 // MulScalar multiplies a point by given scalar value in big.Int and assigns the result to point at first argument.
 func (g *G1) MulScalarBig(r, p *PointG1, e *big.Int) *PointG1 {
 	return g.glvMulBig(r, p, e)
@@ -680,10 +737,7 @@ func (g *G1) MultiExpBig(r *PointG1, points []*PointG1, scalars []*big.Int) (*Po
 		return nil, errors.New("point and scalar vectors should be in same length")
 	}
 
-	c := 3
-	if len(scalars) >= 32 {
-		c = int(math.Ceil(math.Log(float64(len(scalars)))))
-	}
+	c := msmWindowSize(len(scalars))
 
 	bucketSize := (1 << c) - 1
 	windows := make([]PointG1, 255/c+1)
@@ -724,40 +778,59 @@ func (g *G1) MultiExpBig(r *PointG1, points []*PointG1, scalars []*big.Int) (*Po
 // calculates `r = e_0 * P_0 + e_1 * P_1 + ... + e_n * P_n`. Length of points and scalars are expected to be equal,
 // otherwise an error is returned. Result is assigned to point at first argument.
 func (g *G1) MultiExp(r *PointG1, points []*PointG1, scalars []*Fr) (*PointG1, error) {
+	return g.MultiExpParallel(r, points, scalars, 1)
+}
+
+// MultiExpParallel calculates multi exponentiation as MultiExp does with given number of concurrent workers.
+// If number of workers is not positive GOMAXPROCS is used. Result is identical to the result of MultiExp.
+// Given points are not modified.
+func (g *G1) MultiExpParallel(r *PointG1, points []*PointG1, scalars []*Fr, workers int) (*PointG1, error) {
 	if len(points) != len(scalars) {
 		return nil, errors.New("point and scalar vectors should be in same length")
 	}
+	return g.multiExp(r, g.affineCopies(points), scalars, workers, false), nil
+}
 
-	g.AffineBatch(points)
-
-	c := 3
-	if len(scalars) >= 32 {
-		c = int(math.Ceil(math.Log(float64(len(scalars)))))
+// MultiExpComplete calculates multi exponentiation as MultiExpParallel does where buckets are kept in
+// projective coordinates and points are added with complete formulas. It is slightly slower than MultiExpParallel.
+func (g *G1) MultiExpComplete(r *PointG1, points []*PointG1, scalars []*Fr, workers int) (*PointG1, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("point and scalar vectors should be in same length")
 	}
+	return g.multiExp(r, g.affineCopies(points), scalars, workers, true), nil
+}
 
-	bucketSize := (1 << c) - 1
-	windows := make([]*PointG1, 255/c+1)
-	bucket := make([]PointG1, bucketSize)
-
-	for j := 0; j < len(windows); j++ {
+// multiExp calculates multi exponentiation of points in affine form.
+// If complete is set buckets are accumulated in projective coordinates with complete formulas.
+func (g *G1) multiExp(r *PointG1, points []*PointG1, scalars []*Fr, workers int, complete bool) *PointG1 {
+	n := len(scalars)
+	s := newMSMSchedule(n, workers)
+	c := s.c
+	digits := msmSignedDigits(scalars, c, s.windows)
+	partials := make([]PointG1, s.jobs())
 
-		for i := 0; i < bucketSize; i++ {
-			bucket[i].Zero()
+	s.run(func() func(job, window, from, to int) {
+		g := g
+		if s.workers > 1 {
+			g = NewG1()
 		}
-
-		for i := 0; i < len(scalars); i++ {
-			index := bucketSize & int(scalars[i].sliceUint64(c*j))
-			if index != 0 {
-				g.AddMixed(&bucket[index-1], &bucket[index-1], points[i])
+		b := newBucketsG1(g, c)
+		return func(job, window, from, to int) {
+			if complete {
+				b.accumulateProjective(points[from:to], digits[window*n+from:window*n+to])
+			} else {
+				b.accumulate(points[from:to], digits[window*n+from:window*n+to], s.batchAffine(window))
 			}
+			b.sum(&partials[job])
 		}
+	})
 
-		acc, sum := g.New(), g.New()
-		for i := bucketSize - 1; i >= 0; i-- {
-			g.Add(sum, sum, &bucket[i])
-			g.Add(acc, acc, sum)
+	windows := make([]*PointG1, s.windows)
+	for j := range windows {
+		windows[j] = g.New()
+		for k := 0; k < s.chunks; k++ {
+			g.Add(windows[j], windows[j], &partials[j*s.chunks+k])
 		}
-		windows[j] = g.New().Set(acc)
 	}
 
 	g.AffineBatch(windows)
@@ -769,7 +842,7 @@ func (g *G1) MultiExp(r *PointG1, points []*PointG1, scalars []*Fr) (*PointG1, e
 		}
 		g.AddMixed(acc, acc, windows[i])
 	}
-	return r.Set(acc), nil
+	return r.Set(acc)
 }
 
 func (g *G1) ClearCofactor(p *PointG1) *PointG1 {
@@ -791,7 +864,7 @@ func (g *G1) ClearCofactor(p *PointG1) *PointG1 {
 
 // MapToCurve given a byte slice returns a valid G1 point.
 // This mapping function implements the Simplified Shallue-van de Woestijne-Ulas method.
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.6.2
 // Input byte slice should be a valid field element, otherwise an error is returned.
 func (g *G1) MapToCurve(in []byte) (*PointG1, error) {
 	u, err := fromBytes(in)
@@ -808,10 +881,17 @@ func (g *G1) MapToCurve(in []byte) (*PointG1, error) {
 
 // EncodeToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
-// Implementation follows BLS12381G1_XMD:SHA-256_SSWU_NU_ suite at
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// Implementation follows BLS12381G1_XMD:SHA-256_SSWU_NU_ suite of RFC 9380.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8.1
+// Domain separation tags longer than 255 bytes are reduced as defined in RFC 9380 section 5.3.3.
 func (g *G1) EncodeToCurve(msg, domain []byte) (*PointG1, error) {
-	hashRes, err := hashToFpXMDSHA256(msg, domain, 1)
+	return g.EncodeToCurveWith(defaultExpander, msg, domain)
+}
+
+// EncodeToCurveWith is same as EncodeToCurve but uses given expander to hash message into field elements,
+// so that suites such as BLS12381G1_XMD:SHA-512_SSWU_NU_ or BLS12381G1_XOF:SHAKE256_SSWU_NU_ can be built.
+func (g *G1) EncodeToCurveWith(expander Expander, msg, domain []byte) (*PointG1, error) {
+	hashRes, err := hashToFp(expander, msg, domain, 1)
 	if err != nil {
 		return nil, err
 	}
@@ -826,14 +906,96 @@ func (g *G1) EncodeToCurve(msg, domain []byte) (*PointG1, error) {
 
 // HashToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
-// Implementation follows BLS12381G1_XMD:SHA-256_SSWU_RO_ suite at
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// Implementation follows BLS12381G1_XMD:SHA-256_SSWU_RO_ suite of RFC 9380.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8.1
+// Domain separation tags longer than 255 bytes are reduced as defined in RFC 9380 section 5.3.3.
 func (g *G1) HashToCurve(msg, domain []byte) (*PointG1, error) {
-	hashRes, err := hashToFpXMDSHA256(msg, domain, 2)
+	return g.HashToCurveWith(defaultExpander, msg, domain)
+}
+
+// HashToCurveWith is same as HashToCurve but uses given expander to hash message into field elements,
+// so that suites such as BLS12381G1_XMD:SHA-512_SSWU_RO_ or BLS12381G1_XOF:SHAKE256_SSWU_RO_ can be built.
+func (g *G1) HashToCurveWith(expander Expander, msg, domain []byte) (*PointG1, error) {
+	hashRes, err := hashToFp(expander, msg, domain, 2)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToCurveRO(hashRes[0], hashRes[1]), nil
+}
+
+// MapToCurveCT is constant time version of MapToCurve.
+// Running time does not depend on the input field element, so it is suitable for mapping secret values.
+// Constant time property relies on the assembly field arithmetic backend.
+func (g *G1) MapToCurveCT(in []byte) (*PointG1, error) {
+	u, err := fromBytes(in)
 	if err != nil {
 		return nil, err
 	}
-	u0, u1 := hashRes[0], hashRes[1]
+	return g.mapToCurveNUCT(u), nil
+}
+
+// EncodeToCurveCT is constant time version of EncodeToCurve.
+// Running time does not depend on the message given its length, so it is suitable for hashing secret values.
+func (g *G1) EncodeToCurveCT(msg, domain []byte) (*PointG1, error) {
+	hashRes, err := hashToFp(defaultExpander, msg, domain, 1)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToCurveNUCT(hashRes[0]), nil
+}
+
+// HashToCurveCT is constant time version of HashToCurve.
+// Running time does not depend on the message given its length, so it is suitable for hashing secret values.
+func (g *G1) HashToCurveCT(msg, domain []byte) (*PointG1, error) {
+	hashRes, err := hashToFp(defaultExpander, msg, domain, 2)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToCurveROCT(hashRes[0], hashRes[1]), nil
+}
+
+// mapToCurveNUCT maps a field element to curve in constant time.
+// Point additions, doublings and conversion to affine form select results with conditional moves.
+func (g *G1) mapToCurveNUCT(u *fe) *PointG1 {
+	x, y := swuMapG1CT(u)
+	isogenyMapG1CT(x, y)
+	one := new(fe).one()
+	p := &PointG1{*x, *y, *one}
+	g.clearCofactorCT(p)
+	return g.affineCT(p)
+}
+
+// mapToCurveROCT is constant time version of mapToCurveRO.
+// Both points are mapped to the curve with the isogeny before they are added,
+// since doubling formulas used by addCT hold only for curves with a = 0.
+func (g *G1) mapToCurveROCT(u0, u1 *fe) *PointG1 {
+	x0, y0 := swuMapG1CT(u0)
+	x1, y1 := swuMapG1CT(u1)
+	isogenyMapG1CT(x0, y0)
+	isogenyMapG1CT(x1, y1)
+	one := new(fe).one()
+	p0, p1 := &PointG1{*x0, *y0, *one}, &PointG1{*x1, *y1, *one}
+	g.addCT(p0, p0, p1)
+	g.clearCofactorCT(p0)
+	return g.affineCT(p0)
+}
+
+// affineCT is same as Affine but inverts z coordinate in constant time
+// and selects point at infinity with a conditional move.
+func (g *G1) affineCT(p *PointG1) *PointG1 {
+	isZero := ctIsZero(&p[2])
+	t := g.t
+	inverseCT(t[0], &p[2])
+	square(t[1], t[0])
+	mul(&p[0], &p[0], t[1])
+	mul(t[0], t[0], t[1])
+	mul(&p[1], &p[1], t[0])
+	p[2].one()
+	return p.cmov(g.Zero(), isZero)
+}
+
+// mapToCurveRO maps two field elements to curve and returns their sum after cofactor clearing.
+func (g *G1) mapToCurveRO(u0, u1 *fe) *PointG1 {
 	x0, y0 := swuMapG1(u0)
 	x1, y1 := swuMapG1(u1)
 	one := new(fe).one()
@@ -842,5 +1004,5 @@ func (g *G1) HashToCurve(msg, domain []byte) (*PointG1, error) {
 	g.Affine(p0)
 	isogenyMapG1(&p0[0], &p0[1])
 	g.ClearCofactor(p0)
-	return g.Affine(p0), nil
+	return g.Affine(p0)
 }
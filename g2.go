@@ -2,7 +2,7 @@ package bls12381
 
 import (
 	"errors"
-	"math"
+	"fmt"
 	"math/big"
 )
 
@@ -73,42 +73,37 @@ func (g *G2) Q() *big.Int {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G2) FromUncompressed(uncompressed []byte) (*PointG2, error) {
+	return g.DecodeUncompressed(uncompressed, DecodeStrict)
+}
+
+// DecodeUncompressed decodes a point in uncompressed form as FromUncompressed does
+// and applies validations according to the given policy.
+func (g *G2) DecodeUncompressed(uncompressed []byte, policy DecodePolicy) (*PointG2, error) {
 	if len(uncompressed) != 4*fpByteSize {
 		return nil, errors.New("input string length must be equal to 192 bytes")
 	}
 	var in [4 * fpByteSize]byte
 	copy(in[:], uncompressed[:4*fpByteSize])
 	if in[0]&(1<<7) != 0 {
-		return nil, errors.New("compression flag must be zero")
+		return nil, fmt.Errorf("%w: compression flag must be zero", ErrBadFlags)
 	}
 	if in[0]&(1<<5) != 0 {
-		return nil, errors.New("sort flag must be zero")
+		return nil, fmt.Errorf("%w: sort flag must be zero", ErrBadFlags)
 	}
 	if in[0]&(1<<6) != 0 {
-		for i, v := range in {
-			if (i == 0 && v != 0x40) || (i != 0 && v != 0x00) {
-				return nil, errors.New("input string must be zero when infinity flag is set")
-			}
+		if !isZeroBytes(in[:], 0x1f) {
+			return nil, fmt.Errorf("%w: input string must be zero when infinity flag is set", ErrNonCanonical)
 		}
 		return g.Zero(), nil
 	}
 	in[0] &= 0x1f
-	x, err := g.f.fromBytes(in[:2*fpByteSize])
+	p, err := g.fromBytesUnchecked(in[:])
 	if err != nil {
 		return nil, err
 	}
-	y, err := g.f.fromBytes(in[2*fpByteSize:])
-	if err != nil {
+	if err := g.checkPoint(p, policy); err != nil {
 		return nil, err
 	}
-	z := new(fe2).one()
-	p := &PointG2{*x, *y, *z}
-	if !g.IsOnCurve(p) {
-		return nil, errors.New("point is not on curve")
-	}
-	if !g.InCorrectSubgroup(p) {
-		return nil, errors.New("point is not on correct subgroup")
-	}
 	return p, nil
 }
 
@@ -118,7 +113,7 @@ func (g *G2) FromUncompressed(uncompressed []byte) (*PointG2, error) {
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G2) ToUncompressed(p *PointG2) []byte {
 	out := make([]byte, 4*fpByteSize)
-	g.Affine(p)
+	p = g.affine(g.New(), p)
 	if g.IsZero(p) {
 		out[0] |= 1 << 6
 		return out
@@ -133,55 +128,75 @@ func (g *G2) ToUncompressed(p *PointG2) []byte {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G2) FromCompressed(compressed []byte) (*PointG2, error) {
-	if len(compressed) != 2*fpByteSize {
-		return nil, errors.New("input string length must be equal to 96 bytes")
-	}
-	var in [2 * fpByteSize]byte
-	copy(in[:], compressed[:])
-	if in[0]&(1<<7) == 0 {
-		return nil, errors.New("compression flag must be set")
-	}
-	if in[0]&(1<<6) != 0 {
-		// in[0] == (1 << 6) + (1 << 7)
-		for i, v := range in {
-			if (i == 0 && v != 0xc0) || (i != 0 && v != 0x00) {
-				return nil, errors.New("input string must be zero when infinity flag is set")
-			}
-		}
-		return g.Zero(), nil
-	}
-	a := in[0]&(1<<5) != 0
-	in[0] &= 0x1f
-	x, err := g.f.fromBytes(in[:])
+	return g.DecodeCompressed(compressed, DecodeStrict)
+}
+
+// DecodeCompressed decodes a point in compressed form as FromCompressed does
+// and applies validations according to the given policy.
+// Decompressed points are always on curve so only subgroup check is subject to the policy.
+func (g *G2) DecodeCompressed(compressed []byte, policy DecodePolicy) (*PointG2, error) {
+	x, a, err := g.parseCompressed(compressed)
 	if err != nil {
 		return nil, err
 	}
+	if x == nil {
+		return g.Zero(), nil
+	}
 	// solve curve equation
 	y := &fe2{}
 	g.f.square(y, x)
 	g.f.mul(y, y, x)
 	fp2Add(y, y, b2)
 	if ok := g.f.sqrt(y, y); !ok {
-		return nil, errors.New("point is not on curve")
+		return nil, ErrNotOnCurve
 	}
 	if y.signBE() == a {
 		fp2Neg(y, y)
 	}
 	z := new(fe2).one()
 	p := &PointG2{*x, *y, *z}
-	if !g.InCorrectSubgroup(p) {
-		return nil, errors.New("point is not on correct subgroup")
+	if policy.checkSubgroup() && !g.InCorrectSubgroup(p) {
+		return nil, ErrNotInSubgroup
 	}
 	return p, nil
 }
 
+// parseCompressed checks flags of a compressed point and returns x coordinate along with the sort flag.
+// Returned x coordinate is nil if the input is point at infinity.
+func (g *G2) parseCompressed(compressed []byte) (*fe2, bool, error) {
+	if len(compressed) != 2*fpByteSize {
+		return nil, false, errors.New("input string length must be equal to 96 bytes")
+	}
+	var in [2 * fpByteSize]byte
+	copy(in[:], compressed[:])
+	if in[0]&(1<<7) == 0 {
+		return nil, false, fmt.Errorf("%w: compression flag must be set", ErrBadFlags)
+	}
+	if in[0]&(1<<6) != 0 {
+		if in[0]&(1<<5) != 0 {
+			return nil, false, fmt.Errorf("%w: sort flag must be zero when infinity flag is set", ErrBadFlags)
+		}
+		if !isZeroBytes(in[:], 0x1f) {
+			return nil, false, fmt.Errorf("%w: input string must be zero when infinity flag is set", ErrNonCanonical)
+		}
+		return nil, false, nil
+	}
+	a := in[0]&(1<<5) != 0
+	in[0] &= 0x1f
+	x, err := g.f.fromBytes(in[:])
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrNonCanonical, err)
+	}
+	return x, a, nil
+}
+
 // ToCompressed given a G2 point returns bytes in compressed form of the point.
 // Serialization rules are in line with zcash library. See below for details.
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G2) ToCompressed(p *PointG2) []byte {
 	out := make([]byte, 2*fpByteSize)
-	g.Affine(p)
+	p = g.affine(g.New(), p)
 	if g.IsZero(p) {
 		out[0] |= 1 << 6
 	} else {
@@ -194,42 +209,55 @@ func (g *G2) ToCompressed(p *PointG2) []byte {
 	return out
 }
 
+// fromBytesUnchecked parses x and y coordinates without applying any point validation.
 func (g *G2) fromBytesUnchecked(in []byte) (*PointG2, error) {
 	p0, err := g.f.fromBytes(in[:2*fpByteSize])
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrNonCanonical, err)
 	}
 	p1, err := g.f.fromBytes(in[2*fpByteSize:])
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrNonCanonical, err)
 	}
 	p2 := new(fe2).one()
 	return &PointG2{*p0, *p1, *p2}, nil
 }
 
+// checkPoint applies on curve and subgroup checks according to the given policy.
+func (g *G2) checkPoint(p *PointG2, policy DecodePolicy) error {
+	if policy.checkOnCurve() && !g.IsOnCurve(p) {
+		return ErrNotOnCurve
+	}
+	if policy.checkSubgroup() && !g.InCorrectSubgroup(p) {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
 // FromBytes constructs a new point given uncompressed byte input.
 // Input string expected to be 192 bytes and concatenation of x and y values
 // Point (0, 0) is considered as infinity.
+// FromBytes checks that the point is on curve but does not apply subgroup check.
 func (g *G2) FromBytes(in []byte) (*PointG2, error) {
+	return g.DecodeBytes(in, DecodeNoSubgroupCheck)
+}
+
+// DecodeBytes decodes a point in raw (x, y) form as FromBytes does
+// and applies validations according to the given policy.
+func (g *G2) DecodeBytes(in []byte, policy DecodePolicy) (*PointG2, error) {
 	if len(in) != 4*fpByteSize {
 		return nil, errors.New("input string length must be equal to 192 bytes")
 	}
-	p0, err := g.f.fromBytes(in[:2*fpByteSize])
-	if err != nil {
-		return nil, err
-	}
-	p1, err := g.f.fromBytes(in[2*fpByteSize:])
+	p, err := g.fromBytesUnchecked(in)
 	if err != nil {
 		return nil, err
 	}
 	// check if given input points to infinity
-	if p0.isZero() && p1.isZero() {
+	if p[0].isZero() && p[1].isZero() {
 		return g.Zero(), nil
 	}
-	p2 := new(fe2).one()
-	p := &PointG2{*p0, *p1, *p2}
-	if !g.IsOnCurve(p) {
-		return nil, errors.New("point is not on curve")
+	if err := g.checkPoint(p, policy); err != nil {
+		return nil, err
 	}
 	return p, nil
 }
@@ -241,7 +269,7 @@ func (g *G2) ToBytes(p *PointG2) []byte {
 	if g.IsZero(p) {
 		return out
 	}
-	g.Affine(p)
+	p = g.affine(g.New(), p)
 	copy(out[:2*fpByteSize], g.f.toBytes(&p[0]))
 	copy(out[2*fpByteSize:], g.f.toBytes(&p[1]))
 	return out
@@ -325,12 +353,12 @@ func (g *G2) affine(r, p *PointG2) *PointG2 {
 	}
 	if !g.IsAffine(p) {
 		t := g.t
-		g.f.inverse(t[0], &p[2])   // z^-1
-		g.f.square(t[1], t[0])     // z^-2
-		g.f.mulAssign(&r[0], t[1]) // x = x * z^-2
-		g.f.mulAssign(t[0], t[1])  // z^-3
-		g.f.mulAssign(&r[1], t[0]) // y = y * z^-3
-		r[2].one()                 // z = 1
+		g.f.inverse(t[0], &p[2])    // z^-1
+		g.f.square(t[1], t[0])      // z^-2
+		g.f.mul(&r[0], &p[0], t[1]) // x = x * z^-2
+		g.f.mulAssign(t[0], t[1])   // z^-3
+		g.f.mul(&r[1], &p[1], t[0]) // y = y * z^-3
+		r[2].one()                  // z = 1
 	} else {
 		r.Set(p)
 	}
@@ -501,8 +529,38 @@ func (g *G2) Sub(c, a, b *PointG2) *PointG2 {
 }
 
 // MulScalar multiplies a point by given scalar value and assigns the result to point at first argument.
+// Point is expected to be in correct subgroup since multiplication is done with endomorphisms.
 func (g *G2) MulScalar(r, p *PointG2, e *Fr) *PointG2 {
-	return g.glvMulFr(r, p, e)
+	return g.glsMul(r, p, e)
+}
+
+// MulScalarCT is constant time version of MulScalar for secret scalars such as private keys.
+// It uses fixed window method with signed digits where each lookup scans the whole table
+// and additions handle zero and equal inputs without branching.
+func (g *G2) MulScalarCT(r, p *PointG2, e *Fr) *PointG2 {
+	var table [ctMulRowSize]PointG2
+	table[0].Set(p)
+	g.doubleCT(&table[1], p)
+	for d := 2; d < ctMulRowSize; d++ {
+		g.addCT(&table[d], &table[d-1], p)
+	}
+	digits, signs := ctSignedDigits(e, ctMulWindowSize, ctMulWindows)
+	acc, sum, q := g.New(), g.New(), g.New()
+	y := new(fe2)
+	for j := ctMulWindows - 1; j >= 0; j-- {
+		for i := 0; i < ctMulWindowSize; i++ {
+			g.doubleCT(acc, acc)
+		}
+		q.Set(&table[0])
+		for d := 1; d < ctMulRowSize; d++ {
+			q.cmov(&table[d], ctEqualUint64(digits[j], uint64(d+1)))
+		}
+		fp2NegCT(y, &q[1])
+		q[1].cmov(y, signs[j])
+		g.addCT(sum, acc, q)
+		acc.cmov(sum, ctEqualUint64(digits[j], 0)^1)
+	}
+	return r.Set(acc)
 }
 
 // MulScalarBig multiplies a point by given scalar value in big.Int and assigns the result to point at first argument.
@@ -652,6 +710,69 @@ func (g *G2) glvMul(r, p0 *PointG2, v glvVector) *PointG2 {
 	return r.Set(acc)
 }
 
+// glsMul multiplies a point by given scalar with four dimensional decomposition
+// of the scalar over the psi endomorphism and interleaved wNAF of the sub-scalars.
+func (g *G2) glsMul(r, p0 *PointG2, e *Fr) *PointG2 {
+
+	w := glsMulWindowG2
+	l := 1 << (w - 1)
+
+	// prepare tables
+	// tables[i] = {ψ^i(P), 3ψ^i(P), 5ψ^i(P), ...}
+	var tables [4][]*PointG2
+	tables[0] = make([]*PointG2, l)
+	double := g.New()
+	g.Double(double, p0)
+	g.affine(double, double)
+	tables[0][0] = new(PointG2).Set(p0)
+	for i := 1; i < l; i++ {
+		tables[0][i] = new(PointG2)
+		g.AddMixed(tables[0][i], tables[0][i-1], double)
+	}
+	g.AffineBatch(tables[0])
+	for i := 1; i < 4; i++ {
+		tables[i] = make([]*PointG2, l)
+		for j := 0; j < l; j++ {
+			tables[i][j] = new(PointG2).Set(tables[i-1][j])
+			g.psi(tables[i][j])
+		}
+	}
+
+	// recode sub-scalars, odd powers of x are negative
+	k := glsDecompose(e)
+	var nafs [4]nafNumber
+	lenNAF := 0
+	for i := 0; i < 4; i++ {
+		nafs[i] = (&Fr{k[i]}).toWNAF(w)
+		if i%2 == 1 {
+			nafs[i].neg()
+		}
+		if len(nafs[i]) > lenNAF {
+			lenNAF = len(nafs[i])
+		}
+	}
+
+	acc, p1 := g.New(), g.New()
+	for i := lenNAF - 1; i >= 0; i-- {
+		for j := 0; j < 4; j++ {
+			if i >= len(nafs[j]) || nafs[j][i] == 0 {
+				continue
+			}
+			naf := nafs[j][i]
+			if naf > 0 {
+				p1.Set(tables[j][naf>>1])
+			} else {
+				g.Neg(p1, tables[j][(-naf)>>1])
+			}
+			g.AddMixed(acc, acc, p1)
+		}
+		if i != 0 {
+			g.Double(acc, acc)
+		}
+	}
+	return r.Set(acc)
+}
+
 // MultiExpBig calculates multi exponentiation. Scalar values are received as big.Int type.
 // Given pairs of G2 point and scalar values `(P_0, e_0), (P_1, e_1), ... (P_n, e_n)`,
 // calculates `r = e_0 * P_0 + e_1 * P_1 + ... + e_n * P_n`.
@@ -662,10 +783,7 @@ func (g *G2) MultiExpBig(r *PointG2, points []*PointG2, scalars []*big.Int) (*Po
 		return nil, errors.New("point and scalar vectors should be in same length")
 	}
 
-	c := 3
-	if len(scalars) >= 32 {
-		c = int(math.Ceil(math.Log(float64(len(scalars)))))
-	}
+	c := msmWindowSize(len(scalars))
 
 	bucketSize := (1 << c) - 1
 	windows := make([]PointG2, 255/c+1)
@@ -706,40 +824,59 @@ func (g *G2) MultiExpBig(r *PointG2, points []*PointG2, scalars []*big.Int) (*Po
 // calculates `r = e_0 * P_0 + e_1 * P_1 + ... + e_n * P_n`. Length of points and scalars are expected to be equal,
 // otherwise an error is returned. Result is assigned to point at first argument.
 func (g *G2) MultiExp(r *PointG2, points []*PointG2, scalars []*Fr) (*PointG2, error) {
+	return g.MultiExpParallel(r, points, scalars, 1)
+}
+
+// MultiExpParallel calculates multi exponentiation as MultiExp does with given number of concurrent workers.
+// If number of workers is not positive GOMAXPROCS is used. Result is identical to the result of MultiExp.
+// Given points are not modified.
+func (g *G2) MultiExpParallel(r *PointG2, points []*PointG2, scalars []*Fr, workers int) (*PointG2, error) {
 	if len(points) != len(scalars) {
 		return nil, errors.New("point and scalar vectors should be in same length")
 	}
+	return g.multiExp(r, g.affineCopies(points), scalars, workers, false), nil
+}
 
-	g.AffineBatch(points)
-
-	c := 3
-	if len(scalars) >= 32 {
-		c = int(math.Ceil(math.Log(float64(len(scalars)))))
+// MultiExpComplete calculates multi exponentiation as MultiExpParallel does where buckets are kept in
+// projective coordinates and points are added with complete formulas. It is slightly slower than MultiExpParallel.
+func (g *G2) MultiExpComplete(r *PointG2, points []*PointG2, scalars []*Fr, workers int) (*PointG2, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("point and scalar vectors should be in same length")
 	}
+	return g.multiExp(r, g.affineCopies(points), scalars, workers, true), nil
+}
 
-	bucketSize := (1 << c) - 1
-	windows := make([]*PointG2, 255/c+1)
-	bucket := make([]PointG2, bucketSize)
-
-	for j := 0; j < len(windows); j++ {
-
-		for i := 0; i < bucketSize; i++ {
-			bucket[i].Zero()
+// multiExp calculates multi exponentiation of points in affine form.
+// If complete is set buckets are accumulated in projective coordinates with complete formulas.
+func (g *G2) multiExp(r *PointG2, points []*PointG2, scalars []*Fr, workers int, complete bool) *PointG2 {
+	n := len(scalars)
+	s := newMSMSchedule(n, workers)
+	c := s.c
+	digits := msmSignedDigits(scalars, c, s.windows)
+	partials := make([]PointG2, s.jobs())
+
+	s.run(func() func(job, window, from, to int) {
+		g := g
+		if s.workers > 1 {
+			g = NewG2()
 		}
-
-		for i := 0; i < len(scalars); i++ {
-			index := bucketSize & int(scalars[i].sliceUint64(c*j))
-			if index != 0 {
-				g.AddMixed(&bucket[index-1], &bucket[index-1], points[i])
+		b := newBucketsG2(g, c)
+		return func(job, window, from, to int) {
+			if complete {
+				b.accumulateProjective(points[from:to], digits[window*n+from:window*n+to])
+			} else {
+				b.accumulate(points[from:to], digits[window*n+from:window*n+to], s.batchAffine(window))
 			}
+			b.sum(&partials[job])
 		}
+	})
 
-		acc, sum := g.New(), g.New()
-		for i := bucketSize - 1; i >= 0; i-- {
-			g.Add(sum, sum, &bucket[i])
-			g.Add(acc, acc, sum)
+	windows := make([]*PointG2, s.windows)
+	for j := range windows {
+		windows[j] = g.New()
+		for k := 0; k < s.chunks; k++ {
+			g.Add(windows[j], windows[j], &partials[j*s.chunks+k])
 		}
-		windows[j] = g.New().Set(acc)
 	}
 
 	g.AffineBatch(windows)
@@ -751,7 +888,7 @@ func (g *G2) MultiExp(r *PointG2, points []*PointG2, scalars []*Fr) (*PointG2, e
 		}
 		g.AddMixed(acc, acc, windows[i])
 	}
-	return r.Set(acc), nil
+	return r.Set(acc)
 }
 
 // InCorrectSubgroup checks whether given point is in correct subgroup.
@@ -830,7 +967,7 @@ func (g *G2) mulX(p *PointG2) {
 
 // MapToCurve given a byte slice returns a valid G2 point.
 // This mapping function implements the Simplified Shallue-van de Woestijne-Ulas method.
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-05#section-6.6.2
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.6.2
 // Input byte slice should be a valid field element, otherwise an error is returned.
 func (g *G2) MapToCurve(in []byte) (*PointG2, error) {
 	fp2 := g.f
@@ -848,10 +985,17 @@ func (g *G2) MapToCurve(in []byte) (*PointG2, error) {
 
 // EncodeToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
-// Implementation follows BLS12381G1_XMD:SHA-256_SSWU_NU_ suite at
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// Implementation follows BLS12381G2_XMD:SHA-256_SSWU_NU_ suite of RFC 9380.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8.2
+// Domain separation tags longer than 255 bytes are reduced as defined in RFC 9380 section 5.3.3.
 func (g *G2) EncodeToCurve(msg, domain []byte) (*PointG2, error) {
-	hashRes, err := hashToFpXMDSHA256(msg, domain, 2)
+	return g.EncodeToCurveWith(defaultExpander, msg, domain)
+}
+
+// EncodeToCurveWith is same as EncodeToCurve but uses given expander to hash message into field elements,
+// so that suites such as BLS12381G2_XMD:SHA-512_SSWU_NU_ or BLS12381G2_XOF:SHAKE256_SSWU_NU_ can be built.
+func (g *G2) EncodeToCurveWith(expander Expander, msg, domain []byte) (*PointG2, error) {
+	hashRes, err := hashToFp(expander, msg, domain, 2)
 	if err != nil {
 		return nil, err
 	}
@@ -867,15 +1011,98 @@ func (g *G2) EncodeToCurve(msg, domain []byte) (*PointG2, error) {
 
 // HashToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
-// Implementation follows BLS12381G1_XMD:SHA-256_SSWU_RO_ suite at
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// Implementation follows BLS12381G2_XMD:SHA-256_SSWU_RO_ suite of RFC 9380.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8.2
+// Domain separation tags longer than 255 bytes are reduced as defined in RFC 9380 section 5.3.3.
 func (g *G2) HashToCurve(msg, domain []byte) (*PointG2, error) {
-	hashRes, err := hashToFpXMDSHA256(msg, domain, 4)
+	return g.HashToCurveWith(defaultExpander, msg, domain)
+}
+
+// HashToCurveWith is same as HashToCurve but uses given expander to hash message into field elements,
+// so that suites such as BLS12381G2_XMD:SHA-512_SSWU_RO_ or BLS12381G2_XOF:SHAKE256_SSWU_RO_ can be built.
+func (g *G2) HashToCurveWith(expander Expander, msg, domain []byte) (*PointG2, error) {
+	hashRes, err := hashToFp(expander, msg, domain, 4)
 	if err != nil {
 		return nil, err
 	}
+	return g.mapToCurveRO(&fe2{*hashRes[0], *hashRes[1]}, &fe2{*hashRes[2], *hashRes[3]}), nil
+}
+
+// MapToCurveCT is constant time version of MapToCurve.
+// Running time does not depend on the input field element, so it is suitable for mapping secret values.
+// Constant time property relies on the assembly field arithmetic backend.
+func (g *G2) MapToCurveCT(in []byte) (*PointG2, error) {
+	u, err := g.f.fromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToCurveNUCT(u), nil
+}
+
+// EncodeToCurveCT is constant time version of EncodeToCurve.
+// Running time does not depend on the message given its length, so it is suitable for hashing secret values.
+func (g *G2) EncodeToCurveCT(msg, domain []byte) (*PointG2, error) {
+	hashRes, err := hashToFp(defaultExpander, msg, domain, 2)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToCurveNUCT(&fe2{*hashRes[0], *hashRes[1]}), nil
+}
+
+// HashToCurveCT is constant time version of HashToCurve.
+// Running time does not depend on the message given its length, so it is suitable for hashing secret values.
+func (g *G2) HashToCurveCT(msg, domain []byte) (*PointG2, error) {
+	hashRes, err := hashToFp(defaultExpander, msg, domain, 4)
+	if err != nil {
+		return nil, err
+	}
+	return g.mapToCurveROCT(&fe2{*hashRes[0], *hashRes[1]}, &fe2{*hashRes[2], *hashRes[3]}), nil
+}
+
+// mapToCurveNUCT maps a field element to curve in constant time.
+// Point additions, doublings and conversion to affine form select results with conditional moves.
+func (g *G2) mapToCurveNUCT(u *fe2) *PointG2 {
+	x, y := swuMapG2CT(g.f, u)
+	isogenyMapG2CT(g.f, x, y)
+	z := new(fe2).one()
+	q := &PointG2{*x, *y, *z}
+	g.clearCofactorCT(q)
+	return g.affineCT(q)
+}
+
+// mapToCurveROCT is constant time version of mapToCurveRO.
+// Both points are mapped to the curve with the isogeny before they are added,
+// since doubling formulas used by addCT hold only for curves with a = 0.
+func (g *G2) mapToCurveROCT(u0, u1 *fe2) *PointG2 {
+	x0, y0 := swuMapG2CT(g.f, u0)
+	x1, y1 := swuMapG2CT(g.f, u1)
+	isogenyMapG2CT(g.f, x0, y0)
+	isogenyMapG2CT(g.f, x1, y1)
+	z0 := new(fe2).one()
+	z1 := new(fe2).one()
+	p0, p1 := &PointG2{*x0, *y0, *z0}, &PointG2{*x1, *y1, *z1}
+	g.addCT(p0, p0, p1)
+	g.clearCofactorCT(p0)
+	return g.affineCT(p0)
+}
+
+// affineCT is same as Affine but inverts z coordinate in constant time
+// and selects point at infinity with a conditional move.
+func (g *G2) affineCT(p *PointG2) *PointG2 {
+	isZero := ctIsZero2(&p[2])
+	t := g.t
+	g.f.inverseCT(t[0], &p[2])
+	g.f.square(t[1], t[0])
+	g.f.mulAssign(&p[0], t[1])
+	g.f.mulAssign(t[0], t[1])
+	g.f.mulAssign(&p[1], t[0])
+	p[2].one()
+	return p.cmov(g.Zero(), isZero)
+}
+
+// mapToCurveRO maps two field elements to curve and returns their sum after cofactor clearing.
+func (g *G2) mapToCurveRO(u0, u1 *fe2) *PointG2 {
 	fp2 := g.f
-	u0, u1 := &fe2{*hashRes[0], *hashRes[1]}, &fe2{*hashRes[2], *hashRes[3]}
 	x0, y0 := swuMapG2(fp2, u0)
 	x1, y1 := swuMapG2(fp2, u1)
 	z0 := new(fe2).one()
@@ -885,5 +1112,5 @@ func (g *G2) HashToCurve(msg, domain []byte) (*PointG2, error) {
 	g.Affine(p0)
 	isogenyMapG2(fp2, &p0[0], &p0[1])
 	g.ClearCofactor(p0)
-	return g.Affine(p0), nil
+	return g.Affine(p0)
 }
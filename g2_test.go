@@ -417,6 +417,34 @@ func TestG2MultiExp(t *testing.T) {
 	}
 }
 
+func TestG2MultiExpParallel(t *testing.T) {
+	g := NewG2()
+	for _, n := range []int{1, 2, 3, 31, 100, 1000} {
+		bases := make([]*PointG2, n)
+		scalars := make([]*Fr, n)
+		var err error
+		for i := 0; i < n; i++ {
+			scalars[i], err = new(Fr).Rand(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bases[i] = g.randAffine()
+		}
+		expected := g.New()
+		_, _ = g.MultiExp(expected, bases, scalars)
+		for _, workers := range []int{0, 1, 2, 3, 7, 64, 1000} {
+			result := g.New()
+			_, _ = g.MultiExpParallel(result, bases, scalars, workers)
+			if !g.Equal(expected, result) {
+				t.Fatal("parallel multi-exponentiation failed", n, workers)
+			}
+		}
+	}
+	if _, err := g.MultiExpParallel(g.New(), []*PointG2{g.one()}, []*Fr{}, 2); err == nil {
+		t.Fatal("length mismatch must be rejected")
+	}
+}
+
 func TestG2MultiExpBig(t *testing.T) {
 	g := NewG2()
 	for n := 1; n < 1024+1; n = n * 2 {
@@ -733,6 +761,30 @@ func BenchmarkG2MultiExp(t *testing.B) {
 	}
 }
 
+func BenchmarkG2MultiExpParallel(t *testing.B) {
+	g := NewG2()
+	n := 1 << 14
+	bases := make([]*PointG2, n)
+	scalars := make([]*Fr, n)
+	var err error
+	for i := 0; i < n; i++ {
+		scalars[i], err = new(Fr).Rand(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bases[i] = g.randAffine()
+	}
+	for _, workers := range []int{1, 2, 4, 8} {
+		t.Run(fmt.Sprint(workers), func(t *testing.B) {
+			result := g.New()
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				_, _ = g.MultiExpParallel(result, bases, scalars, workers)
+			}
+		})
+	}
+}
+
 func BenchmarkG2ClearCofactor(t *testing.B) {
 	g2 := NewG2()
 	a := g2.rand()
@@ -762,3 +814,40 @@ func BenchmarkG2SWUMap(t *testing.B) {
 		}
 	}
 }
+
+func TestG2HashToCurveRFC9380(t *testing.T) {
+	for _, name := range []string{
+		"BLS12381G2_XMD-SHA-256_SSWU_RO_",
+		"BLS12381G2_XMD-SHA-256_SSWU_NU_",
+	} {
+		suite := hashToCurveSuite{}
+		readHashToCurveVectors(t, name, &suite)
+		g := NewG2()
+		for i, v := range suite.Vectors {
+			count := 1
+			hash := g.EncodeToCurve
+			if suite.RandomOracle {
+				count = 2
+				hash = g.HashToCurve
+			}
+			u, err := hashToFpXMDSHA256([]byte(v.Msg), []byte(suite.DST), 2*count)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := 0; j < count; j++ {
+				uj := append(toBytes(u[2*j+1]), toBytes(u[2*j])...)
+				if !bytes.Equal(uj, fromHexFe(v.U[j])) {
+					t.Fatal("hash to field fails", name, i)
+				}
+			}
+			p, err := hash([]byte(v.Msg), []byte(suite.DST))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := append(fromHexFe(v.P.X), fromHexFe(v.P.Y)...)
+			if !bytes.Equal(g.ToBytes(p), expected) {
+				t.Fatal("hash to curve fails", name, i)
+			}
+		}
+	}
+}
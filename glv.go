@@ -2,6 +2,7 @@ package bls12381
 
 import (
 	"math/big"
+	"math/bits"
 )
 
 // Guide to Pairing Based Cryptography
@@ -198,3 +199,25 @@ func (g *G2) glvEndomorphism(r, p *PointG2) {
 	g.f.phi(&r[0], &t[0])
 	r[2].one()
 }
+
+// GLS decomposition for G2
+// Psi endomorphism acts on G2 as multiplication by p which is equal to x modulo q.
+// Since q = x^4 - x^2 + 1, a scalar less than q is written in base |x| with four digits below 2^64 as
+// k = k0 + k1 * |x| + k2 * |x|^2 + k3 * |x|^3 = k0 - k1 * x + k2 * x^2 - k3 * x^3 where x is negative.
+
+var glsMulWindowG2 uint = 4
+
+// glsDecompose returns base |x| digits of the scalar.
+func glsDecompose(e *Fr) [4]uint64 {
+	var k [4]uint64
+	a := *e
+	for i := 0; i < 3; i++ {
+		var rem uint64
+		for j := 3; j >= 0; j-- {
+			a[j], rem = bits.Div64(rem, a[j], x)
+		}
+		k[i] = rem
+	}
+	k[3] = a[0]
+	return k
+}
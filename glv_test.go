@@ -132,3 +132,68 @@ func TestGLVConstruction(t *testing.T) {
 		}
 	})
 }
+
+func TestGLSConstruction(t *testing.T) {
+	t.Run("Endomorphism", func(t *testing.T) {
+		g := NewG2()
+		// psi(P) = x * P where x is negative
+		xModQ := new(big.Int).Sub(qBig, new(big.Int).SetUint64(x))
+		for i := 0; i < fuz; i++ {
+			p0, p1 := g.randCorrect(), g.New()
+			g.MulScalarBig(p1, p0, xModQ)
+			g.psi(p0)
+			if !g.Equal(p0, p1) {
+				t.Fatal("psi(P) = x * P")
+			}
+		}
+	})
+	t.Run("Scalar Decomposition", func(t *testing.T) {
+		scalars := []*Fr{new(Fr), new(Fr).setUint64(x), new(Fr).Set(&q)}
+		scalars[2][0]--
+		for i := 0; i < fuz; i++ {
+			m, _ := new(Fr).Rand(rand.Reader)
+			scalars = append(scalars, m)
+		}
+		xBig := new(big.Int).SetUint64(x)
+		for _, m := range scalars {
+			k := glsDecompose(m)
+			acc := new(big.Int)
+			for i := 3; i >= 0; i-- {
+				if k[i] >= x {
+					t.Fatal("bad scalar component", i)
+				}
+				acc.Mul(acc, xBig)
+				acc.Add(acc, new(big.Int).SetUint64(k[i]))
+			}
+			if acc.Cmp(m.ToBig()) != 0 {
+				t.Fatal("scalar decomposing failed")
+			}
+		}
+	})
+	t.Run("Multiplication", func(t *testing.T) {
+		g := NewG2()
+		scalars := []*Fr{new(Fr), new(Fr).setUint64(1), new(Fr).setUint64(x), new(Fr).Set(&q)}
+		scalars[3][0]--
+		for i := 0; i < fuz; i++ {
+			m, _ := new(Fr).Rand(rand.Reader)
+			scalars = append(scalars, m)
+		}
+		for i, m := range scalars {
+			p := g.randCorrect()
+			r0, r1 := g.New(), g.New()
+			g.wnafMulFr(r0, p, m)
+			g.glsMul(r1, p, m)
+			if !g.Equal(r0, r1) {
+				t.Fatal("gls multiplication failed", i)
+			}
+			g.MulScalar(r1, p, m)
+			if !g.Equal(r0, r1) {
+				t.Fatal("scalar multiplication failed", i)
+			}
+			g.glsMul(r1, g.Zero(), m)
+			if !g.IsZero(r1) {
+				t.Fatal("gls multiplication of zero failed")
+			}
+		}
+	})
+}
@@ -2,4 +2,7 @@ module github.com/kilic/bls12-381
 
 go 1.13
 
-require golang.org/x/sys v0.0.0-20210124154548-22da62e12c0c
+require (
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
+	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1
+)
@@ -64,10 +64,27 @@ func (g *GT) ToBytes(e *E) []byte {
 }
 
 // IsValid checks whether given target group element is in correct subgroup.
+// An element a is in cyclotomic subgroup if a^(p^4 - p^2 + 1) = 1 and such element is in
+// target group if a^p = a^x where x is the curve parameter.
+// See https://eprint.iacr.org/2021/1130 for the check.
 func (g *GT) IsValid(e *E) bool {
-	r := g.New()
-	g.fp12.exp(r, e, qBig)
-	return r.isOne()
+	if e.isZero() {
+		return false
+	}
+	t0, t1 := new(fe12).set(e), new(fe12)
+	// a^(p^4) * a == a^(p^2)
+	g.fp12.frobeniusMap2(t0)
+	t1.set(t0)
+	g.fp12.frobeniusMap2(t1)
+	g.fp12.mulAssign(t1, e)
+	if !t0.equal(t1) {
+		return false
+	}
+	// a^p == a^x
+	t0.set(e)
+	g.fp12.frobeniusMap1(t0)
+	g.fp12.cyclotomicExpX(t1, e)
+	return t0.equal(t1)
 }
 
 // New initializes a new target group element which is equal to one
@@ -97,6 +114,8 @@ func (g *GT) Square(c, a *E) {
 }
 
 // Exp exponents an element `a` by a scalar `s` and assigns the result to the element in first argument.
+// Exp does not use the Frobenius decomposition. It squares and multiplies over bits of the scalar, which is not reduced,
+// so it is valid for any element of cyclotomic subgroup. ExpFr and MultiExp are faster for elements in target group.
 func (g *GT) Exp(c, a *E, s *big.Int) {
 	g.fp12.cyclotomicExp(c, a, s)
 }
@@ -105,3 +124,127 @@ func (g *GT) Exp(c, a *E, s *big.Int) {
 func (g *GT) Inverse(c, a *E) {
 	g.fp12.inverse(c, a)
 }
+
+// ToCompressedT2 compresses target group element into 288 bytes with T2 torus compression.
+// An element e = g + h * w of the norm one torus is represented by c = (1 + g) / h
+// and one is represented as zero. Given element is expected to be in target group.
+func (g *GT) ToCompressedT2(e *E) []byte {
+	fp6 := g.fp12.fp6
+	c := new(fe6)
+	// only one and minus one have zero h in the torus
+	if !e[1].isZero() {
+		t0, t1 := new(fe6), new(fe6)
+		fp6.inverse(t0, &e[1])
+		fp6Add(t1, &e[0], fp6.one())
+		fp6.mul(c, t1, t0)
+	}
+	return fp6.toBytes(c)
+}
+
+// FromCompressedT2 expects 288 byte input which is output of ToCompressedT2
+// and returns target group element. FromCompressedT2 returns error
+// if decompressed element is not on correct subgroup.
+func (g *GT) FromCompressedT2(in []byte) (*E, error) {
+	fp6 := g.fp12.fp6
+	c, err := fp6.fromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	if c.isZero() {
+		return g.New(), nil
+	}
+	// e = (c + w) / (c - w) = (c^2 + v + 2c * w) / (c^2 - v)
+	e, t0, t1, t2 := g.New(), new(fe6), new(fe6), new(fe6)
+	fp6.square(t0, c)
+	t1.set(t0)
+	fp2Add(&t0[1], &t0[1], fp6.fp2.one())
+	fp2Sub(&t1[1], &t1[1], fp6.fp2.one())
+	fp6.inverse(t2, t1)
+	fp6.mul(&e[0], t0, t2)
+	fp6Double(t0, c)
+	fp6.mul(&e[1], t0, t2)
+	if !g.IsValid(e) {
+		return nil, errors.New("invalid element")
+	}
+	return e, nil
+}
+
+// ToCompressedT6 compresses target group element into 192 bytes with T6 torus compression.
+// Target group is in T6(Fp2). For T2 representation c = c0 + c1 * v + c2 * v^2 of an element of T2(Fp6)
+// the element is in T6(Fp2) if and only if 3 * c0 * c1 = 1 + 3 * ξ * c2^2, so c0 is recovered from c1 and c2.
+// An element is serialized as c2 and c1. Unless c1 is zero then c2 and c0 are serialized and
+// the most significant bit of the first byte is set. One is represented as zero.
+// Given element is expected to be in target group.
+func (g *GT) ToCompressedT6(e *E) []byte {
+	fp6 := g.fp12.fp6
+	fp2 := fp6.fp2
+	out := make([]byte, 4*fpByteSize)
+	if e[1].isZero() {
+		return out
+	}
+	c, t0, t1 := new(fe6), new(fe6), new(fe6)
+	fp6.inverse(t0, &e[1])
+	fp6Add(t1, &e[0], fp6.one())
+	fp6.mul(c, t1, t0)
+	copy(out[:2*fpByteSize], fp2.toBytes(&c[2]))
+	if !c[1].isZero() {
+		copy(out[2*fpByteSize:], fp2.toBytes(&c[1]))
+	} else {
+		copy(out[2*fpByteSize:], fp2.toBytes(&c[0]))
+		out[0] |= 1 << 7
+	}
+	return out
+}
+
+// FromCompressedT6 expects 192 byte input which is output of ToCompressedT6
+// and returns target group element. FromCompressedT6 returns error
+// if decompressed element is not on correct subgroup.
+func (g *GT) FromCompressedT6(in []byte) (*E, error) {
+	if len(in) != 4*fpByteSize {
+		return nil, errors.New("input string length must be equal to 192 bytes")
+	}
+	fp6 := g.fp12.fp6
+	fp2 := fp6.fp2
+	var buf [4 * fpByteSize]byte
+	copy(buf[:], in)
+	flag := buf[0]&(1<<7) != 0
+	buf[0] &= 0x7f
+	c := new(fe6)
+	c2, err := fp2.fromBytes(buf[:2*fpByteSize])
+	if err != nil {
+		return nil, err
+	}
+	ci, err := fp2.fromBytes(buf[2*fpByteSize:])
+	if err != nil {
+		return nil, err
+	}
+	c[2].set(c2)
+	// t = 1 + 3 * ξ * c2^2
+	t0, t1 := new(fe2), new(fe2)
+	fp2.square(t0, c2)
+	mulByNonResidue(t1, t0)
+	fp2Double(t0, t1)
+	fp2Add(t0, t0, t1)
+	fp2Add(t0, t0, fp2.one())
+	if flag {
+		// c1 = 0 requires 1 + 3 * ξ * c2^2 = 0
+		if !t0.isZero() {
+			return nil, errors.New("invalid element")
+		}
+		c[0].set(ci)
+	} else {
+		if ci.isZero() {
+			if !c2.isZero() {
+				return nil, errors.New("invalid element")
+			}
+			return g.New(), nil
+		}
+		// c0 = (1 + 3 * ξ * c2^2) / (3 * c1)
+		c[1].set(ci)
+		fp2Double(t1, ci)
+		fp2Add(t1, t1, ci)
+		fp2.inverse(t1, t1)
+		fp2.mul(&c[0], t0, t1)
+	}
+	return g.FromCompressedT2(fp6.toBytes(c))
+}
@@ -0,0 +1,127 @@
+package bls12381
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// Frobenius map acts on target group as exponentiation by p and p = x mod q where x is the curve parameter.
+// Since q = x^4 - x^2 + 1 < |x|^4 a scalar is written with 64 bit digits in base |x| as
+// s = s0 + s1 * |x| + s2 * |x|^2 + s3 * |x|^3 so that
+// a^s = a^s0 * conj(a^p)^s1 * (a^(p^2))^s2 * conj(a^(p^3))^s3
+// where bases of odd powers are conjugated since x is negative.
+// Exponentiation is then a simultaneous exponentiation of four bases by 64 bit digits with cyclotomic squarings,
+// and multi exponentiation applies bucket method to four times as many bases with 64 bit digits.
+// Elements are expected to be in target group and results are not correct for other elements,
+// so Exp, which is left as square and multiply over bits of the scalar, should be used for elements
+// which are only known to be in cyclotomic subgroup.
+
+// gtDigits is number of base |x| digits of a scalar given by glsDecompose.
+const gtDigits = 4
+
+// frobeniusBases returns a, conj(a^p), a^(p^2), conj(a^(p^3)).
+func (g *GT) frobeniusBases(a *E) [gtDigits]fe12 {
+	var b [gtDigits]fe12
+	b[0].set(a)
+	for i := 1; i < gtDigits; i++ {
+		b[i].set(&b[i-1])
+		g.fp12.frobeniusMap1(&b[i])
+		fp12Conjugate(&b[i], &b[i])
+	}
+	return b
+}
+
+// ExpFr exponents an element `a` in target group by a scalar `s` and assigns the result to the element in first argument.
+func (g *GT) ExpFr(c, a *E, s *Fr) {
+	bases := g.frobeniusBases(a)
+	d := glsDecompose(s)
+	// table[i] is product of bases selected by bits of i
+	var table [1 << gtDigits]fe12
+	table[0].one()
+	for i := 1; i < len(table); i++ {
+		g.fp12.mul(&table[i], &table[i&(i-1)], &bases[bits.TrailingZeros(uint(i))])
+	}
+	z := new(fe12).one()
+	for i := 63; i >= 0; i-- {
+		g.fp12.cyclotomicSquare(z)
+		var k uint64
+		for j := range d {
+			k |= (d[j] >> uint(i) & 1) << uint(j)
+		}
+		if k != 0 {
+			g.fp12.mulAssign(z, &table[k])
+		}
+	}
+	c.set(z)
+}
+
+// MultiExp calculates multi exponentiation of elements in target group.
+// Given bases and scalars must be in same length.
+func (g *GT) MultiExp(c *E, bases []*E, scalars []*Fr) (*E, error) {
+	if len(bases) != len(scalars) {
+		return nil, errors.New("base and scalar vectors should be in same length")
+	}
+	n := len(bases) * gtDigits
+	points := make([]fe12, 0, n)
+	digits := make([]uint64, 0, n)
+	for i := range bases {
+		b := g.frobeniusBases(bases[i])
+		d := glsDecompose(scalars[i])
+		points = append(points, b[:]...)
+		digits = append(digits, d[:]...)
+	}
+	w := gtMultiExpWindow(n)
+	mask := uint64(1)<<w - 1
+	buckets := make([]fe12, mask)
+	filled := make([]bool, mask)
+	acc, running, sum := new(fe12).one(), new(fe12), new(fe12)
+	for j := int((64+w-1)/w) - 1; j >= 0; j-- {
+		for i := uint(0); i < w; i++ {
+			g.fp12.cyclotomicSquare(acc)
+		}
+		for k := range filled {
+			filled[k] = false
+		}
+		shift := uint(j) * w
+		for i := range points {
+			k := digits[i] >> shift & mask
+			if k == 0 {
+				continue
+			}
+			if filled[k-1] {
+				g.fp12.mulAssign(&buckets[k-1], &points[i])
+			} else {
+				buckets[k-1].set(&points[i])
+				filled[k-1] = true
+			}
+		}
+		// sum = product of bucket[k]^(k+1)
+		running.one()
+		sum.one()
+		started := false
+		for k := len(buckets) - 1; k >= 0; k-- {
+			if filled[k] {
+				g.fp12.mulAssign(running, &buckets[k])
+				started = true
+			}
+			if started {
+				g.fp12.mulAssign(sum, running)
+			}
+		}
+		g.fp12.mulAssign(acc, sum)
+	}
+	return c.set(acc), nil
+}
+
+// gtMultiExpWindow returns window size which minimizes number of multiplications
+// of bucket method for n bases with 64 bit digits.
+func gtMultiExpWindow(n int) uint {
+	best, bestCost := uint(1), -1
+	for w := uint(1); w <= 16; w++ {
+		cost := int((64+w-1)/w) * (n + 2<<w)
+		if bestCost < 0 || cost < bestCost {
+			best, bestCost = w, cost
+		}
+	}
+	return best
+}
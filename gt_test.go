@@ -0,0 +1,277 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func (g *GT) randCorrect() *E {
+	bls := NewEngine()
+	return bls.AddPair(bls.G1.randCorrect(), bls.G2.randCorrect()).Result()
+}
+
+func TestGTCompressionT2(t *testing.T) {
+	g := NewGT()
+	for i := 0; i < fuz; i++ {
+		e0 := g.randCorrect()
+		compressed := g.ToCompressedT2(e0)
+		if len(compressed) != 288 {
+			t.Fatal("bad compressed length")
+		}
+		e1, err := g.FromCompressedT2(compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !e0.Equal(e1) {
+			t.Fatal("compression roundtrip failed")
+		}
+	}
+	one := g.New()
+	compressed := g.ToCompressedT2(one)
+	if !bytes.Equal(compressed, make([]byte, 288)) {
+		t.Fatal("one must be compressed to zero")
+	}
+	e, err := g.FromCompressedT2(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.IsOne() {
+		t.Fatal("one compression roundtrip failed")
+	}
+	// elements of the torus out of correct subgroup are rejected
+	c, _ := new(fe6).rand(rand.Reader)
+	if _, err := g.FromCompressedT2(g.fp12.fp6.toBytes(c)); err == nil {
+		t.Fatal("element out of subgroup must be rejected")
+	}
+}
+
+func TestGTCompressionT6(t *testing.T) {
+	g := NewGT()
+	for i := 0; i < fuz; i++ {
+		e0 := g.randCorrect()
+		compressed := g.ToCompressedT6(e0)
+		if len(compressed) != 192 {
+			t.Fatal("bad compressed length")
+		}
+		e1, err := g.FromCompressedT6(compressed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !e0.Equal(e1) {
+			t.Fatal("compression roundtrip failed")
+		}
+	}
+	one := g.New()
+	compressed := g.ToCompressedT6(one)
+	if !bytes.Equal(compressed, make([]byte, 192)) {
+		t.Fatal("one must be compressed to zero")
+	}
+	e, err := g.FromCompressedT6(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.IsOne() {
+		t.Fatal("one compression roundtrip failed")
+	}
+	// elements of the torus out of correct subgroup are rejected
+	r, _ := new(fe2).rand(rand.Reader)
+	in := append(make([]byte, 2*fpByteSize), g.fp12.fp2().toBytes(r)...)
+	if _, err := g.FromCompressedT6(in); err == nil {
+		t.Fatal("element out of subgroup must be rejected")
+	}
+	// c2 must satisfy the torus equation when c1 is zero
+	in = append(g.fp12.fp2().toBytes(r), make([]byte, 2*fpByteSize)...)
+	in[0] |= 1 << 7
+	if _, err := g.FromCompressedT6(in); err == nil {
+		t.Fatal("element out of torus must be rejected")
+	}
+	in[0] &= 0x7f
+	if _, err := g.FromCompressedT6(in); err == nil {
+		t.Fatal("non zero input must be rejected when c1 is zero")
+	}
+}
+
+func TestGTIsValid(t *testing.T) {
+	g := NewGT()
+	isValidNaive := func(e *E) bool {
+		r := g.New()
+		g.fp12.exp(r, e, qBig)
+		return r.isOne()
+	}
+	check := func(e *E, expected bool) {
+		t.Helper()
+		if isValidNaive(e) != expected {
+			t.Fatal("bad test vector")
+		}
+		if g.IsValid(e) != expected {
+			t.Fatal("subgroup check does not match naive check")
+		}
+	}
+	check(g.New(), true)
+	check(new(E), false)
+	for i := 0; i < fuz; i++ {
+		check(g.randCorrect(), true)
+		// random element is not in cyclotomic subgroup
+		a, _ := new(fe12).rand(rand.Reader)
+		check(a, false)
+		// a^((p^6 - 1) * (p^2 + 1)) is in cyclotomic subgroup but not in target group
+		b := new(fe12)
+		fp12Conjugate(b, a)
+		g.fp12.inverse(a, a)
+		g.fp12.mulAssign(b, a)
+		a.set(b)
+		g.fp12.frobeniusMap2(a)
+		g.fp12.mulAssign(a, b)
+		check(a, false)
+	}
+}
+
+func BenchmarkGTIsValid(t *testing.B) {
+	g := NewGT()
+	e := g.randCorrect()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		g.IsValid(e)
+	}
+}
+
+func TestGTExp(t *testing.T) {
+	g := NewGT()
+	qMinusOne := new(big.Int).Sub(qBig, big.NewInt(1))
+	scalars := []*Fr{new(Fr).Zero(), new(Fr).One(), new(Fr).fromBig(qMinusOne), new(Fr).setUint64(x)}
+	for i := 0; i < fuz; i++ {
+		s, _ := new(Fr).Rand(rand.Reader)
+		scalars = append(scalars, s)
+	}
+	for _, s := range scalars {
+		a := g.randCorrect()
+		expected, r := g.New(), g.New()
+		g.fp12.cyclotomicExp(expected, a, s.ToBig())
+		g.ExpFr(r, a, s)
+		if !r.Equal(expected) {
+			t.Fatal("exponentiation failed")
+		}
+		g.Exp(r, a, s.ToBig())
+		if !r.Equal(expected) {
+			t.Fatal("exponentiation failed")
+		}
+		// output aliases input
+		g.ExpFr(a, a, s)
+		if !a.Equal(expected) {
+			t.Fatal("exponentiation in place failed")
+		}
+	}
+}
+
+func TestGTExpCyclotomic(t *testing.T) {
+	// Exp is valid for elements of cyclotomic subgroup which are not in target group
+	g, e := NewGT(), NewEngine()
+	f := e.AddPair(e.G1.One(), e.G2.One()).MillerLoop()
+	// a = f^((p^6 - 1) * (p^2 + 1))
+	b, a := new(fe12), new(fe12)
+	fp12Conjugate(b, f)
+	g.fp12.inverse(f, f)
+	g.fp12.mulAssign(b, f)
+	a.set(b)
+	g.fp12.frobeniusMap2(a)
+	g.fp12.mulAssign(a, b)
+	if g.IsValid(a) {
+		t.Fatal("element is expected to be out of target group")
+	}
+	s, _ := new(Fr).Rand(rand.Reader)
+	expected, r := g.New(), g.New()
+	g.fp12.exp(expected, a, s.ToBig())
+	g.Exp(r, a, s.ToBig())
+	if !r.Equal(expected) {
+		t.Fatal("exponentiation of cyclotomic element failed")
+	}
+}
+
+func TestGTMultiExp(t *testing.T) {
+	g := NewGT()
+	for _, n := range []int{0, 1, 2, 5, 40} {
+		bases, scalars := make([]*E, n), make([]*Fr, n)
+		expected, r := g.New(), g.New()
+		for i := 0; i < n; i++ {
+			bases[i] = g.randCorrect()
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			g.fp12.cyclotomicExp(r, bases[i], scalars[i].ToBig())
+			g.Mul(expected, expected, r)
+		}
+		if n > 2 {
+			// zero exponent and repeated base
+			scalars[0].Zero()
+			bases[2].set(bases[1])
+			expected.one()
+			for i := 0; i < n; i++ {
+				g.fp12.cyclotomicExp(r, bases[i], scalars[i].ToBig())
+				g.Mul(expected, expected, r)
+			}
+		}
+		if _, err := g.MultiExp(r, bases, scalars); err != nil {
+			t.Fatal(err)
+		}
+		if !r.Equal(expected) {
+			t.Fatalf("multi exponentiation failed with %d bases", n)
+		}
+	}
+	if _, err := g.MultiExp(g.New(), []*E{g.New()}, []*Fr{}); err == nil {
+		t.Fatal("vectors in different lengths must be rejected")
+	}
+}
+
+func BenchmarkGTExp(t *testing.B) {
+	g := NewGT()
+	a := g.randCorrect()
+	s, _ := new(Fr).Rand(rand.Reader)
+	r := g.New()
+	t.Run("Naive", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.fp12.cyclotomicExp(r, a, s.ToBig())
+		}
+	})
+	t.Run("Frobenius", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.ExpFr(r, a, s)
+		}
+	})
+}
+
+func BenchmarkGTMultiExp(t *testing.B) {
+	g := NewGT()
+	for _, n := range []int{8, 64} {
+		bases, scalars := make([]*E, n), make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			bases[i] = g.randCorrect()
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+		}
+		r := g.New()
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.B) {
+			for i := 0; i < t.N; i++ {
+				g.MultiExp(r, bases, scalars)
+			}
+		})
+	}
+}
+
+func BenchmarkGTDecompression(t *testing.B) {
+	g := NewGT()
+	e := g.randCorrect()
+	t.Run("T2", func(t *testing.B) {
+		compressed := g.ToCompressedT2(e)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			g.FromCompressedT2(compressed)
+		}
+	})
+	t.Run("T6", func(t *testing.B) {
+		compressed := g.ToCompressedT6(e)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			g.FromCompressedT6(compressed)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package bls12381
+
+import "io"
+
+// Streaming hash to curve feeds the message into the expander incrementally,
+// so that large messages do not need to be held in memory. Output of a hasher
+// is identical to HashToCurve and HashToCurveWith for the same message.
+
+// HasherG1 hashes a message written in pieces to G1.
+// It implements io.Writer and io.ReaderFrom.
+type HasherG1 struct {
+	g      *G1
+	stream ExpanderStream
+}
+
+// NewHasher returns a streaming hasher following BLS12381G1_XMD:SHA-256_SSWU_RO_ suite.
+func (g *G1) NewHasher(domain []byte) (*HasherG1, error) {
+	return g.NewHasherWith(defaultExpander, domain)
+}
+
+// NewHasherWith returns a streaming hasher which uses given expander to hash message into field elements.
+func (g *G1) NewHasherWith(expander StreamingExpander, domain []byte) (*HasherG1, error) {
+	stream, err := expander.NewStream(domain, 2*64)
+	if err != nil {
+		return nil, err
+	}
+	return &HasherG1{NewG1(), stream}, nil
+}
+
+// HashToCurveFromReader hashes the message read from r until EOF to G1.
+// Result is same as HashToCurve applied to the whole message.
+func (g *G1) HashToCurveFromReader(r io.Reader, domain []byte) (*PointG1, error) {
+	h, err := g.NewHasher(domain)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return h.Sum()
+}
+
+// Write appends p to the message.
+func (h *HasherG1) Write(p []byte) (int, error) {
+	return h.stream.Write(p)
+}
+
+// ReadFrom appends data read from r until EOF to the message.
+func (h *HasherG1) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(h.stream, r)
+}
+
+// Sum returns hash of the message written so far and resets the hasher.
+func (h *HasherG1) Sum() (*PointG1, error) {
+	u, err := fieldElementsFromBytes(h.stream.Sum(), 2)
+	if err != nil {
+		return nil, err
+	}
+	return h.g.mapToCurveRO(u[0], u[1]), nil
+}
+
+// Reset discards the message written so far.
+func (h *HasherG1) Reset() {
+	h.stream.Reset()
+}
+
+// HasherG2 hashes a message written in pieces to G2.
+// It implements io.Writer and io.ReaderFrom.
+type HasherG2 struct {
+	g      *G2
+	stream ExpanderStream
+}
+
+// NewHasher returns a streaming hasher following BLS12381G2_XMD:SHA-256_SSWU_RO_ suite.
+func (g *G2) NewHasher(domain []byte) (*HasherG2, error) {
+	return g.NewHasherWith(defaultExpander, domain)
+}
+
+// NewHasherWith returns a streaming hasher which uses given expander to hash message into field elements.
+func (g *G2) NewHasherWith(expander StreamingExpander, domain []byte) (*HasherG2, error) {
+	stream, err := expander.NewStream(domain, 4*64)
+	if err != nil {
+		return nil, err
+	}
+	return &HasherG2{NewG2(), stream}, nil
+}
+
+// HashToCurveFromReader hashes the message read from r until EOF to G2.
+// Result is same as HashToCurve applied to the whole message.
+func (g *G2) HashToCurveFromReader(r io.Reader, domain []byte) (*PointG2, error) {
+	h, err := g.NewHasher(domain)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return h.Sum()
+}
+
+// Write appends p to the message.
+func (h *HasherG2) Write(p []byte) (int, error) {
+	return h.stream.Write(p)
+}
+
+// ReadFrom appends data read from r until EOF to the message.
+func (h *HasherG2) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(h.stream, r)
+}
+
+// Sum returns hash of the message written so far and resets the hasher.
+func (h *HasherG2) Sum() (*PointG2, error) {
+	u, err := fieldElementsFromBytes(h.stream.Sum(), 4)
+	if err != nil {
+		return nil, err
+	}
+	return h.g.mapToCurveRO(&fe2{*u[0], *u[1]}, &fe2{*u[2], *u[3]}), nil
+}
+
+// Reset discards the message written so far.
+func (h *HasherG2) Reset() {
+	h.stream.Reset()
+}
@@ -0,0 +1,162 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"testing"
+)
+
+func TestExpanderStream(t *testing.T) {
+	domain := []byte("QUUX-V01-CS02-with-expander")
+	msg := make([]byte, 1000)
+	_, _ = rand.Read(msg)
+	for _, expander := range []StreamingExpander{
+		defaultExpander,
+		NewXMDExpander(sha512.New),
+		NewSHAKE128Expander(),
+		NewSHAKE256Expander(),
+	} {
+		for _, outLen := range []int{1, 32, 256, 1000} {
+			expected, err := expander.Expand(msg, domain, outLen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stream, err := expander.NewStream(domain, outLen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// stream must be reusable after sum
+			for k := 0; k < 2; k++ {
+				for i := 0; i < len(msg); i += 7 {
+					to := i + 7
+					if to > len(msg) {
+						to = len(msg)
+					}
+					_, _ = stream.Write(msg[i:to])
+				}
+				if !bytes.Equal(stream.Sum(), expected) {
+					t.Fatal("stream expansion failed", outLen)
+				}
+			}
+			_, _ = stream.Write([]byte("garbage"))
+			stream.Reset()
+			_, _ = stream.Write(msg)
+			if !bytes.Equal(stream.Sum(), expected) {
+				t.Fatal("stream reset failed", outLen)
+			}
+		}
+	}
+}
+
+func TestG1HashToCurveStream(t *testing.T) {
+	suite := hashToCurveSuite{}
+	readHashToCurveVectors(t, "BLS12381G1_XMD-SHA-256_SSWU_RO_", &suite)
+	g := NewG1()
+	for i, v := range suite.Vectors {
+		p, err := g.HashToCurveFromReader(bytes.NewReader([]byte(v.Msg)), []byte(suite.DST))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := append(fromHexFe(v.P.X), fromHexFe(v.P.Y)...)
+		if !bytes.Equal(g.ToBytes(p), expected) {
+			t.Fatal("streaming hash to curve fails", i)
+		}
+	}
+	domain := []byte("BLS12381G1_XMD:SHA-512_SSWU_RO_TEST")
+	msg := make([]byte, 100000)
+	_, _ = rand.Read(msg)
+	expander := NewXMDExpander(sha512.New)
+	expected, err := g.HashToCurveWith(expander, msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := g.NewHasherWith(expander, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(msg); i += 4099 {
+		to := i + 4099
+		if to > len(msg) {
+			to = len(msg)
+		}
+		_, _ = h.Write(msg[i:to])
+	}
+	p, err := h.Sum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(p, expected) {
+		t.Fatal("streaming hash to curve fails")
+	}
+	if _, err := h.ReadFrom(bytes.NewReader(msg)); err != nil {
+		t.Fatal(err)
+	}
+	p, err = h.Sum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(p, expected) {
+		t.Fatal("hasher must be reusable after sum")
+	}
+	if _, err := g.NewHasher([]byte{}); err == nil {
+		t.Fatal("empty domain must be rejected")
+	}
+}
+
+func TestG2HashToCurveStream(t *testing.T) {
+	suite := hashToCurveSuite{}
+	readHashToCurveVectors(t, "BLS12381G2_XMD-SHA-256_SSWU_RO_", &suite)
+	g := NewG2()
+	for i, v := range suite.Vectors {
+		p, err := g.HashToCurveFromReader(bytes.NewReader([]byte(v.Msg)), []byte(suite.DST))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := append(fromHexFe(v.P.X), fromHexFe(v.P.Y)...)
+		if !bytes.Equal(g.ToBytes(p), expected) {
+			t.Fatal("streaming hash to curve fails", i)
+		}
+	}
+	domain := []byte("BLS12381G2_XOF:SHAKE256_SSWU_RO_TEST")
+	msg := make([]byte, 100000)
+	_, _ = rand.Read(msg)
+	expander := NewSHAKE256Expander()
+	expected, err := g.HashToCurveWith(expander, msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := g.NewHasherWith(expander, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(msg); i += 4099 {
+		to := i + 4099
+		if to > len(msg) {
+			to = len(msg)
+		}
+		_, _ = h.Write(msg[i:to])
+	}
+	p, err := h.Sum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(p, expected) {
+		t.Fatal("streaming hash to curve fails")
+	}
+	_, _ = h.Write([]byte("garbage"))
+	h.Reset()
+	if _, err := h.ReadFrom(bytes.NewReader(msg)); err != nil {
+		t.Fatal(err)
+	}
+	p, err = h.Sum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(p, expected) {
+		t.Fatal("hasher must be reusable after reset")
+	}
+	if _, err := g.NewHasher([]byte{}); err == nil {
+		t.Fatal("empty domain must be rejected")
+	}
+}
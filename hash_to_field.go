@@ -3,13 +3,96 @@ package bls12381
 import (
 	"crypto/sha256"
 	"errors"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/sha3"
 )
 
+// Hashing to field follows RFC 9380.
+// https://www.rfc-editor.org/rfc/rfc9380.html
+
+// oversizeDSTPrefix is used to reduce domain separation tags longer than 255 bytes.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.3.3
+const oversizeDSTPrefix = "H2C-OVERSIZE-DST-"
+
+// Expander expands a message into uniformly random bytes
+// as expand_message functions defined in RFC 9380 section 5.3.
+type Expander interface {
+	// Expand returns outLen bytes given message and domain separation tag.
+	Expand(msg, domain []byte, outLen int) ([]byte, error)
+}
+
+// StreamingExpander is an Expander which can also consume the message incrementally.
+type StreamingExpander interface {
+	Expander
+	// NewStream returns a stream that expands the message written into it to outLen bytes.
+	NewStream(domain []byte, outLen int) (ExpanderStream, error)
+}
+
+// ExpanderStream accepts a message in pieces through Write.
+// Sum returns expanded bytes of the message written so far and resets the stream.
+type ExpanderStream interface {
+	io.Writer
+	Sum() []byte
+	Reset()
+}
+
+// XOF is an extendable output function such as SHAKE128 or SHAKE256.
+type XOF interface {
+	io.Writer
+	io.Reader
+	Reset()
+}
+
+type xmdExpander struct {
+	newHash func() hash.Hash
+}
+
+// NewXMDExpander returns expand_message_xmd expander over the hash function given by its constructor.
+// For example NewXMDExpander(sha256.New) is the expander of BLS12381G1_XMD:SHA-256_SSWU_RO_ suite.
+func NewXMDExpander(newHash func() hash.Hash) StreamingExpander {
+	return &xmdExpander{newHash}
+}
+
+type xofExpander struct {
+	newXOF func() XOF
+	k      int
+}
+
+// NewXOFExpander returns expand_message_xof expander over the extendable output function given by its constructor.
+// k is the target security level in bits and is used to reduce long domain separation tags.
+func NewXOFExpander(newXOF func() XOF, k int) StreamingExpander {
+	return &xofExpander{newXOF, k}
+}
+
+// NewSHAKE128Expander returns expand_message_xof expander over SHAKE128 with 128 bits security level.
+func NewSHAKE128Expander() StreamingExpander {
+	return NewXOFExpander(func() XOF { return sha3.NewShake128() }, 128)
+}
+
+// NewSHAKE256Expander returns expand_message_xof expander over SHAKE256 with 256 bits security level.
+func NewSHAKE256Expander() StreamingExpander {
+	return NewXOFExpander(func() XOF { return sha3.NewShake256() }, 256)
+}
+
+// defaultExpander is expand_message_xmd with SHA-256 which is used by suites of RFC 9380 for BLS12-381.
+var defaultExpander = NewXMDExpander(sha256.New)
+
 func hashToFpXMDSHA256(msg []byte, domain []byte, count int) ([]*fe, error) {
-	randBytes, err := expandMsgSHA256XMD(msg, domain, count*64)
+	return hashToFp(defaultExpander, msg, domain, count)
+}
+
+func hashToFp(expander Expander, msg []byte, domain []byte, count int) ([]*fe, error) {
+	randBytes, err := expander.Expand(msg, domain, count*64)
 	if err != nil {
 		return nil, err
 	}
+	return fieldElementsFromBytes(randBytes, count)
+}
+
+func fieldElementsFromBytes(randBytes []byte, count int) ([]*fe, error) {
+	var err error
 	els := make([]*fe, count)
 	for i := 0; i < count; i++ {
 		els[i], err = from64Bytes(randBytes[i*64 : (i+1)*64])
@@ -20,16 +103,61 @@ func hashToFpXMDSHA256(msg []byte, domain []byte, count int) ([]*fe, error) {
 	return els, nil
 }
 
-func expandMsgSHA256XMD(msg []byte, domain []byte, outLen int) ([]byte, error) {
-	h := sha256.New()
-	domainLen := uint8(len(domain))
-	if domainLen > 255 {
-		return nil, errors.New("invalid domain length")
+// Expand implements expand_message_xmd.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.3.1
+func (e *xmdExpander) Expand(msg []byte, domain []byte, outLen int) ([]byte, error) {
+	stream, err := e.NewStream(domain, outLen)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = stream.Write(msg)
+	return stream.Sum(), nil
+}
+
+// NewStream returns expand_message_xmd stream where message is fed into b_0 computation.
+func (e *xmdExpander) NewStream(domain []byte, outLen int) (ExpanderStream, error) {
+	h := e.newHash()
+	if len(domain) == 0 {
+		return nil, errors.New("domain separation tag must not be empty")
+	}
+	if len(domain) > 255 {
+		// DST = H("H2C-OVERSIZE-DST-" || a_very_long_DST)
+		_, _ = h.Write([]byte(oversizeDSTPrefix))
+		_, _ = h.Write(domain)
+		domain = h.Sum(nil)
+		h.Reset()
+	}
+	ell := (outLen + h.Size() - 1) / h.Size()
+	if ell > 255 || outLen > 65535 || outLen < 1 {
+		return nil, errors.New("invalid output length")
 	}
+	s := &xmdStream{h, domain, outLen}
+	s.Reset()
+	return s, nil
+}
+
+type xmdStream struct {
+	h      hash.Hash
+	domain []byte
+	outLen int
+}
+
+// Reset starts b_0 computation with Z_pad.
+func (s *xmdStream) Reset() {
+	s.h.Reset()
+	_, _ = s.h.Write(make([]byte, s.h.BlockSize()))
+}
+
+func (s *xmdStream) Write(msg []byte) (int, error) {
+	return s.h.Write(msg)
+}
+
+func (s *xmdStream) Sum() []byte {
+	h, domain, outLen := s.h, s.domain, s.outLen
+	domainLen := uint8(len(domain))
+	ell := (outLen + h.Size() - 1) / h.Size()
 	// DST_prime = DST || I2OSP(len(DST), 1)
 	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
-	_, _ = h.Write(make([]byte, h.BlockSize()))
-	_, _ = h.Write(msg)
 	_, _ = h.Write([]byte{uint8(outLen >> 8), uint8(outLen)})
 	_, _ = h.Write([]byte{0})
 	_, _ = h.Write(domain)
@@ -45,9 +173,8 @@ func expandMsgSHA256XMD(msg []byte, domain []byte, outLen int) ([]byte, error) {
 	b1 := h.Sum(nil)
 
 	// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
-	ell := (outLen + h.Size() - 1) / h.Size()
 	bi := b1
-	out := make([]byte, outLen)
+	out := make([]byte, ell*h.Size())
 	for i := 1; i < ell; i++ {
 		h.Reset()
 		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
@@ -66,5 +193,67 @@ func expandMsgSHA256XMD(msg []byte, domain []byte, outLen int) ([]byte, error) {
 	}
 	// b_ell
 	copy(out[(ell-1)*h.Size():], bi[:])
-	return out[:outLen], nil
+	s.Reset()
+	return out[:outLen]
+}
+
+// Expand implements expand_message_xof.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.3.2
+func (e *xofExpander) Expand(msg []byte, domain []byte, outLen int) ([]byte, error) {
+	stream, err := e.NewStream(domain, outLen)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = stream.Write(msg)
+	return stream.Sum(), nil
+}
+
+// NewStream returns expand_message_xof stream where message is fed into the extendable output function.
+func (e *xofExpander) NewStream(domain []byte, outLen int) (ExpanderStream, error) {
+	h := e.newXOF()
+	if len(domain) == 0 {
+		return nil, errors.New("domain separation tag must not be empty")
+	}
+	if len(domain) > 255 {
+		// DST = H("H2C-OVERSIZE-DST-" || a_very_long_DST, ceil(2 * k / 8))
+		_, _ = h.Write([]byte(oversizeDSTPrefix))
+		_, _ = h.Write(domain)
+		domain = make([]byte, (2*e.k+7)/8)
+		if _, err := io.ReadFull(h, domain); err != nil {
+			return nil, err
+		}
+		h.Reset()
+	}
+	if outLen > 65535 || outLen < 1 {
+		return nil, errors.New("invalid output length")
+	}
+	return &xofStream{h, domain, outLen}, nil
+}
+
+type xofStream struct {
+	h      XOF
+	domain []byte
+	outLen int
+}
+
+func (s *xofStream) Reset() {
+	s.h.Reset()
+}
+
+func (s *xofStream) Write(msg []byte) (int, error) {
+	return s.h.Write(msg)
+}
+
+func (s *xofStream) Sum() []byte {
+	h, domain, outLen := s.h, s.domain, s.outLen
+	// DST_prime = DST || I2OSP(len(DST), 1)
+	// msg_prime = msg || I2OSP(len_in_bytes, 2) || DST_prime
+	// uniform_bytes = H(msg_prime, len_in_bytes)
+	_, _ = h.Write([]byte{uint8(outLen >> 8), uint8(outLen)})
+	_, _ = h.Write(domain)
+	_, _ = h.Write([]byte{uint8(len(domain))})
+	out := make([]byte, outLen)
+	_, _ = io.ReadFull(h, out)
+	s.Reset()
+	return out
 }
@@ -0,0 +1,173 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// RFC 9380 test vectors
+// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-J
+
+type hashToCurveVector struct {
+	Msg string
+	P   struct{ X, Y string }
+	U   []string
+}
+
+type hashToCurveSuite struct {
+	Ciphersuite  string
+	DST          string
+	RandomOracle bool
+	Vectors      []hashToCurveVector
+}
+
+type expandMessageVector struct {
+	Msg          string
+	LenInBytes   string `json:"len_in_bytes"`
+	UniformBytes string `json:"uniform_bytes"`
+}
+
+type expandMessageSuite struct {
+	DST   string
+	Hash  string
+	Name  string
+	Tests []expandMessageVector
+}
+
+func readHashToCurveVectors(t *testing.T, name string, v interface{}) {
+	data, err := ioutil.ReadFile("tests/hash_to_curve/" + name + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fromHexFe decodes comma separated field element coordinates given in RFC 9380 vectors
+// and returns them in the serialization order of the library.
+func fromHexFe(s string) []byte {
+	coeffs := strings.Split(s, ",")
+	out := []byte{}
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		out = append(out, fromHex(fpByteSize, coeffs[i])...)
+	}
+	return out
+}
+
+func TestExpanders(t *testing.T) {
+	for name, expander := range map[string]Expander{
+		"expand_message_xmd_SHA256_38":    NewXMDExpander(sha256.New),
+		"expand_message_xmd_SHA256_256":   NewXMDExpander(sha256.New),
+		"expand_message_xmd_SHA512_38":    NewXMDExpander(sha512.New),
+		"expand_message_xof_SHAKE128_36":  NewSHAKE128Expander(),
+		"expand_message_xof_SHAKE128_256": NewSHAKE128Expander(),
+		"expand_message_xof_SHAKE256_36":  NewSHAKE256Expander(),
+	} {
+		suite := expandMessageSuite{}
+		readHashToCurveVectors(t, name, &suite)
+		for i, v := range suite.Tests {
+			outLen, err := strconv.ParseInt(v.LenInBytes, 0, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := expander.Expand([]byte(v.Msg), []byte(suite.DST), int(outLen))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, _ := hex.DecodeString(v.UniformBytes)
+			if !bytes.Equal(out, expected) {
+				t.Fatal("expand message failed", name, i)
+			}
+		}
+	}
+}
+
+func TestExpanderLimits(t *testing.T) {
+	msg := []byte("msg")
+	domain := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	if _, err := defaultExpander.Expand(msg, domain, 255*32); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := defaultExpander.Expand(msg, domain, 255*32+1); err == nil {
+		t.Fatal("output length must be limited")
+	}
+	if _, err := defaultExpander.Expand(msg, domain, 0); err == nil {
+		t.Fatal("zero output length must be rejected")
+	}
+	if _, err := defaultExpander.Expand(msg, []byte{}, 32); err == nil {
+		t.Fatal("empty domain must be rejected")
+	}
+	// domain up to 255 bytes is used as is, longer domain is reduced
+	long := bytes.Repeat([]byte{'a'}, 256)
+	out0, err := defaultExpander.Expand(msg, long[:255], 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out1, err := defaultExpander.Expand(msg, long, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(out0, out1) {
+		t.Fatal("long domain must be reduced")
+	}
+}
+
+func TestExpanderXOFLimits(t *testing.T) {
+	expander := NewSHAKE128Expander()
+	msg := []byte("msg")
+	domain := []byte("QUUX-V01-CS02-with-expander-SHAKE128")
+	if _, err := expander.Expand(msg, domain, 65535); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expander.Expand(msg, domain, 65536); err == nil {
+		t.Fatal("output length must be limited")
+	}
+	if _, err := expander.Expand(msg, []byte{}, 32); err == nil {
+		t.Fatal("empty domain must be rejected")
+	}
+}
+
+func TestHashToCurveWith(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	msg := []byte("abc")
+	for _, v := range []struct {
+		expander Expander
+		domain   string
+	}{
+		{NewXMDExpander(sha512.New), "BLS12381G1_XMD:SHA-512_SSWU_RO_TEST"},
+		{NewSHAKE128Expander(), "BLS12381G1_XOF:SHAKE128_SSWU_RO_TEST"},
+		{NewSHAKE256Expander(), "BLS12381G1_XOF:SHAKE256_SSWU_RO_TEST"},
+	} {
+		domain := []byte(v.domain)
+		p0, err := g1.HashToCurveWith(v.expander, msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p1, _ := g1.HashToCurve(msg, domain)
+		if !g1.InCorrectSubgroup(p0) || g1.Equal(p0, p1) {
+			t.Fatal("hash to curve with expander failed, g1")
+		}
+		q0, err := g2.HashToCurveWith(v.expander, msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q1, _ := g2.HashToCurve(msg, domain)
+		if !g2.InCorrectSubgroup(q0) || g2.Equal(q0, q1) {
+			t.Fatal("hash to curve with expander failed, g2")
+		}
+		if _, err := g1.EncodeToCurveWith(v.expander, msg, domain); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := g2.EncodeToCurveWith(v.expander, msg, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
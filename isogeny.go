@@ -1,7 +1,16 @@
 package bls12381
 
-// isogenyMapG1 applies 11-isogeny map for BLS12-381 G1 defined at draft-irtf-cfrg-hash-to-curve-06.
+// isogenyMapG1 applies 11-isogeny map for BLS12-381 G1 defined at RFC 9380.
 func isogenyMapG1(x, y *fe) {
+	isogenyMapG1With(x, y, inverse)
+}
+
+// isogenyMapG1CT is isogenyMapG1 with constant time inversions.
+func isogenyMapG1CT(x, y *fe) {
+	isogenyMapG1With(x, y, inverseCT)
+}
+
+func isogenyMapG1With(x, y *fe, inverse func(c, a *fe)) {
 	xNum, xDen, yNum, yDen := new(fe), new(fe), new(fe), new(fe)
 	xNum.set(isogenyConstansG1[0][15])
 	xDen.set(isogenyConstansG1[1][15])
@@ -24,11 +33,23 @@ func isogenyMapG1(x, y *fe) {
 	mul(y, y, yNum)
 }
 
-// isogenyMapG2 applies 3-isogeny map for BLS12-381 G2 defined at draft-irtf-cfrg-hash-to-curve-06.
+// isogenyMapG2 applies 3-isogeny map for BLS12-381 G2 defined at RFC 9380.
 func isogenyMapG2(e *fp2, x, y *fe2) {
 	if e == nil {
 		e = newFp2()
 	}
+	isogenyMapG2With(e, x, y, e.inverse)
+}
+
+// isogenyMapG2CT is isogenyMapG2 with constant time inversions.
+func isogenyMapG2CT(e *fp2, x, y *fe2) {
+	if e == nil {
+		e = newFp2()
+	}
+	isogenyMapG2With(e, x, y, e.inverseCT)
+}
+
+func isogenyMapG2With(e *fp2, x, y *fe2, inverse func(c, a *fe2)) {
 	xNum := new(fe2).set(isogenyConstantsG2[0][3])
 	xDen := new(fe2).set(x)
 	yNum := new(fe2).set(isogenyConstantsG2[2][3])
@@ -57,13 +78,81 @@ func isogenyMapG2(e *fp2, x, y *fe2) {
 	fp2AddAssign(yNum, isogenyConstantsG2[2][0])
 	fp2AddAssign(yDen, isogenyConstantsG2[3][0])
 
-	e.inverse(xDen, xDen)
-	e.inverse(yDen, yDen)
+	inverse(xDen, xDen)
+	inverse(yDen, yDen)
 	e.mul(x, xNum, xDen)
 	e.mulAssign(yNum, yDen)
 	e.mulAssign(y, yNum)
 }
 
+// isogenyPreimageG1 returns a point of the isogenous curve which is mapped to (x, y) with isogenyMapG1.
+// x coordinate of the preimage is a root of xNum(X) - x * xDen(X).
+func isogenyPreimageG1(x, y *fe) (*fe, *fe, bool) {
+	f := make([]fe, 16)
+	t := new(fe)
+	for i := range f {
+		mul(t, isogenyConstansG1[1][i], x)
+		sub(&f[i], isogenyConstansG1[0][i], t)
+	}
+	x0, ok := polyRoot(f)
+	if !ok {
+		return nil, nil, false
+	}
+	// y = y0 * yNum(x0) / yDen(x0)
+	x1, y0 := new(fe).set(x0), new(fe).one()
+	isogenyMapG1(x1, y0)
+	if !x1.equal(x) || y0.isZero() {
+		return nil, nil, false
+	}
+	inverse(y0, y0)
+	mul(y0, y0, y)
+	// check the curve equation of isogenous curve
+	params := swuParamsForG1
+	square(t, x0)
+	add(t, t, params.a)
+	mul(t, t, x0)
+	add(t, t, params.b)
+	square(x1, y0)
+	if !t.equal(x1) {
+		return nil, nil, false
+	}
+	return x0, y0, true
+}
+
+// isogenyPreimageG2 returns a point of the isogenous curve which is mapped to (x, y) with isogenyMapG2.
+// x coordinate of the preimage is a root of xNum(X) - x * xDen(X).
+func isogenyPreimageG2(e *fp2, x, y *fe2) (*fe2, *fe2, bool) {
+	f := make([]fe2, 4)
+	t := e.new()
+	for i := range f {
+		e.mul(t, isogenyConstantsG2[1][i], x)
+		fp2Sub(&f[i], isogenyConstantsG2[0][i], t)
+	}
+	x0, ok := e.poly2Root(f)
+	if !ok {
+		return nil, nil, false
+	}
+	// y = y0 * yNum(x0) / yDen(x0)
+	x1, y0 := e.new().set(x0), e.one()
+	isogenyMapG2(e, x1, y0)
+	if !x1.equal(x) || y0.isZero() {
+		return nil, nil, false
+	}
+	e.inverse(y0, y0)
+	e.mul(y0, y0, y)
+	// check the curve equation of isogenous curve
+	params := swuParamsForG2
+	e.square(t, x0)
+	fp2Add(t, t, params.a)
+	e.mul(t, t, x0)
+	fp2Add(t, t, params.b)
+	e.square(x1, y0)
+	if !t.equal(x1) {
+		return nil, nil, false
+	}
+	return x0, y0, true
+}
+
 var isogenyConstansG1 = [4][16]*fe{
 	{
 		{0x4d18b6f3af00131c, 0x19fa219793fee28c, 0x3f2885f1467f19ae, 0x23dcea34f2ffb304, 0xd15b58d2ffc00054, 0x0913be200a20bef4},
@@ -0,0 +1,198 @@
+package bls12381
+
+import (
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// Points are marshaled in compressed form as defined in zkcrypto library.
+// Unmarshaling applies on curve and subgroup checks.
+// Scalars are marshaled in 32 bytes big endian form of the regular (non Montgomery) representation
+// and must be less than the group order.
+// Target group elements are marshaled in 576 bytes and checked to be in the correct subgroup.
+// Text form is hex encoding of the binary form and JSON form is the text form as a JSON string.
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (p PointG1) MarshalBinary() ([]byte, error) {
+	return NewG1().ToCompressed(&p), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (p *PointG1) UnmarshalBinary(data []byte) error {
+	r, err := NewG1().FromCompressed(data)
+	if err != nil {
+		return err
+	}
+	p.Set(r)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p PointG1) MarshalText() ([]byte, error) {
+	return marshalText(p)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *PointG1) UnmarshalText(text []byte) error {
+	return unmarshalText(p, text)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p PointG1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *PointG1) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (p PointG2) MarshalBinary() ([]byte, error) {
+	return NewG2().ToCompressed(&p), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (p *PointG2) UnmarshalBinary(data []byte) error {
+	r, err := NewG2().FromCompressed(data)
+	if err != nil {
+		return err
+	}
+	p.Set(r)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p PointG2) MarshalText() ([]byte, error) {
+	return marshalText(p)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *PointG2) UnmarshalText(text []byte) error {
+	return unmarshalText(p, text)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p PointG2) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *PointG2) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(p, data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e Fr) MarshalBinary() ([]byte, error) {
+	return e.ToBytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *Fr) UnmarshalBinary(data []byte) error {
+	if len(data) != frByteSize {
+		return errors.New("input string length must be equal to 32 bytes")
+	}
+	if new(big.Int).SetBytes(data).Cmp(qBig) != -1 {
+		return errors.New("must be less than group order")
+	}
+	e.FromBytes(data)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e Fr) MarshalText() ([]byte, error) {
+	return marshalText(e)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *Fr) UnmarshalText(text []byte) error {
+	return unmarshalText(e, text)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e Fr) MarshalJSON() ([]byte, error) {
+	return marshalJSON(e)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Fr) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(e, data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e E) MarshalBinary() ([]byte, error) {
+	return NewGT().ToBytes(&e), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *E) UnmarshalBinary(data []byte) error {
+	r, err := NewGT().FromBytes(data)
+	if err != nil {
+		return err
+	}
+	e.Set(r)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e E) MarshalText() ([]byte, error) {
+	return marshalText(e)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *E) UnmarshalText(text []byte) error {
+	return unmarshalText(e, text)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e E) MarshalJSON() ([]byte, error) {
+	return marshalJSON(e)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *E) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(e, data)
+}
+
+func marshalText(m encoding.BinaryMarshaler) ([]byte, error) {
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, hex.EncodedLen(len(data)))
+	hex.Encode(out, data)
+	return out, nil
+}
+
+func unmarshalText(u encoding.BinaryUnmarshaler, text []byte) error {
+	s := strings.TrimPrefix(string(text), "0x")
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalBinary(data)
+}
+
+func marshalJSON(m encoding.BinaryMarshaler) ([]byte, error) {
+	text, err := marshalText(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func unmarshalJSON(u encoding.BinaryUnmarshaler, data []byte) error {
+	// null is a no-op by convention
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return unmarshalText(u, []byte(s))
+}
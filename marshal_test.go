@@ -0,0 +1,160 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalPoints(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	type S struct {
+		P1  PointG1
+		P2  PointG2
+		PP1 *PointG1
+		PP2 *PointG2
+	}
+	for i := 0; i < fuz; i++ {
+		s0 := S{*g1.randCorrect(), *g2.randCorrect(), g1.randCorrect(), g2.randCorrect()}
+		// json
+		{
+			data, err := json.Marshal(s0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s1 := S{}
+			if err := json.Unmarshal(data, &s1); err != nil {
+				t.Fatal(err)
+			}
+			if !g1.Equal(&s0.P1, &s1.P1) || !g1.Equal(s0.PP1, s1.PP1) {
+				t.Fatal("json roundtrip failed, g1")
+			}
+			if !g2.Equal(&s0.P2, &s1.P2) || !g2.Equal(s0.PP2, s1.PP2) {
+				t.Fatal("json roundtrip failed, g2")
+			}
+		}
+		// gob
+		{
+			buf := new(bytes.Buffer)
+			if err := gob.NewEncoder(buf).Encode(s0); err != nil {
+				t.Fatal(err)
+			}
+			s1 := S{}
+			if err := gob.NewDecoder(buf).Decode(&s1); err != nil {
+				t.Fatal(err)
+			}
+			if !g1.Equal(&s0.P1, &s1.P1) || !g1.Equal(s0.PP1, s1.PP1) {
+				t.Fatal("gob roundtrip failed, g1")
+			}
+			if !g2.Equal(&s0.P2, &s1.P2) || !g2.Equal(s0.PP2, s1.PP2) {
+				t.Fatal("gob roundtrip failed, g2")
+			}
+		}
+	}
+	// encoding is compressed zkcrypto encoding
+	{
+		p := g1.randCorrect()
+		text, err := p.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(text) != hex.EncodeToString(g1.ToCompressed(p)) {
+			t.Fatal("bad text encoding, g1")
+		}
+		q := g2.randCorrect()
+		text, err = q.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(text) != hex.EncodeToString(g2.ToCompressed(q)) {
+			t.Fatal("bad text encoding, g2")
+		}
+	}
+	// infinity
+	{
+		data, err := g1.Zero().MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := g1.One()
+		if err := p.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if !g1.IsZero(p) {
+			t.Fatal("infinity roundtrip failed")
+		}
+	}
+	// points out of correct subgroup are rejected
+	{
+		data := g1.ToCompressed(g1.rand())
+		if err := new(PointG1).UnmarshalBinary(data); err == nil {
+			t.Fatal("point out of subgroup must be rejected, g1")
+		}
+		data = g2.ToCompressed(g2.rand())
+		if err := new(PointG2).UnmarshalBinary(data); err == nil {
+			t.Fatal("point out of subgroup must be rejected, g2")
+		}
+	}
+}
+
+func TestMarshalFr(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		data, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := new(Fr)
+		if err := json.Unmarshal(data, b); err != nil {
+			t.Fatal(err)
+		}
+		if !a.Equal(b) {
+			t.Fatal("json roundtrip failed")
+		}
+		text, err := a.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Zero()
+		if err := b.UnmarshalText(append([]byte("0x"), text...)); err != nil {
+			t.Fatal(err)
+		}
+		if !a.Equal(b) {
+			t.Fatal("text roundtrip failed")
+		}
+	}
+	if err := new(Fr).UnmarshalBinary(q.bytes()); err == nil {
+		t.Fatal("non canonical scalar must be rejected")
+	}
+	if err := new(Fr).UnmarshalBinary(make([]byte, 31)); err == nil {
+		t.Fatal("short input must be rejected")
+	}
+}
+
+func TestMarshalGT(t *testing.T) {
+	bls := NewEngine()
+	e0 := bls.AddPair(bls.G1.randCorrect(), bls.G2.randCorrect()).Result()
+	data, err := json.Marshal(e0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e1 := new(E)
+	if err := json.Unmarshal(data, e1); err != nil {
+		t.Fatal(err)
+	}
+	if !e0.Equal(e1) {
+		t.Fatal("json roundtrip failed")
+	}
+	// elements out of correct subgroup are rejected
+	r, _ := new(fe12).rand(rand.Reader)
+	data, err = r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := new(E).UnmarshalBinary(data); err == nil {
+		t.Fatal("element out of subgroup must be rejected")
+	}
+}
@@ -0,0 +1,127 @@
+package bls12381
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// Pairing is split into Miller loop and final exponentiation. Miller loop results of disjoint sets
+// of pairs can be calculated separately, for example in different goroutines or on different machines,
+// and their product gives the same result with a single final exponentiation as if all pairs
+// were added to a single engine. Miller loop results are not elements of target group, so they are
+// encoded with MillerLoopToBytes and decoded with MillerLoopFromBytes which does not apply subgroup check.
+
+// MillerLoop calculates Miller loop of added pairs without final exponentiation and resets the engine.
+// Result is one if no pairs are added.
+func (e *Engine) MillerLoop() *E {
+	f := e.fp12.one()
+	if len(e.pairs) != 0 {
+		e.millerLoop(f)
+	}
+	e.Reset()
+	return f
+}
+
+// MillerLoopParallel is MillerLoop with pairs partitioned across given number of concurrent workers.
+// If number of workers is not positive GOMAXPROCS is used.
+func (e *Engine) MillerLoopParallel(workers int) *E {
+	f := e.fp12.one()
+	e.millerLoopParallel(f, workers)
+	e.Reset()
+	return f
+}
+
+// CheckParallel is Check with Miller loop distributed across given number of concurrent workers.
+// If number of workers is not positive GOMAXPROCS is used.
+func (e *Engine) CheckParallel(workers int) bool {
+	return e.calculateParallel(workers).isOne()
+}
+
+// ResultParallel is Result with Miller loop distributed across given number of concurrent workers.
+// If number of workers is not positive GOMAXPROCS is used.
+func (e *Engine) ResultParallel(workers int) *E {
+	r := e.calculateParallel(workers)
+	e.Reset()
+	return r
+}
+
+func (e *Engine) calculateParallel(workers int) *fe12 {
+	f := e.fp12.one()
+	if len(e.pairs) == 0 {
+		return f
+	}
+	e.millerLoopParallel(f, workers)
+	e.finalExp(f)
+	return f
+}
+
+// millerLoopParallel splits pairs into contiguous chunks, runs Miller loop of each chunk
+// on a separate engine and multiplies partial results into f. Pairs are only read by workers.
+func (e *Engine) millerLoopParallel(f *fe12, workers int) {
+	n := len(e.pairs)
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		f.one()
+		if n != 0 {
+			e.millerLoop(f)
+		}
+		return
+	}
+	partials := make([]fe12, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			worker := NewEngine()
+			worker.pairs = e.pairs[w*n/workers : (w+1)*n/workers]
+			worker.millerLoop(&partials[w])
+		}(w)
+	}
+	wg.Wait()
+	f.set(&partials[0])
+	for w := 1; w < workers; w++ {
+		e.fp12.mulAssign(f, &partials[w])
+	}
+}
+
+// MulMillerLoops returns product of given Miller loop results.
+func (e *Engine) MulMillerLoops(partials ...*E) *E {
+	f := e.fp12.one()
+	for _, p := range partials {
+		e.fp12.mulAssign(f, p)
+	}
+	return f
+}
+
+// FinalExponentiate applies final exponentiation to a Miller loop result and returns the result
+// as target group element. Given Miller loop result is not modified.
+func (e *Engine) FinalExponentiate(f *E) *E {
+	r := new(E).Set(f)
+	e.finalExp(r)
+	return r
+}
+
+// MillerLoopToBytes serializes a Miller loop result into 576 bytes.
+func (e *Engine) MillerLoopToBytes(f *E) []byte {
+	return e.fp12.toBytes(f)
+}
+
+// MillerLoopFromBytes decodes a Miller loop result encoded with MillerLoopToBytes.
+// It checks that coefficients are canonical and the result is not zero.
+func (e *Engine) MillerLoopFromBytes(in []byte) (*E, error) {
+	f, err := e.fp12.fromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	if f.isZero() {
+		return nil, errors.New("miller loop result must not be zero")
+	}
+	return f, nil
+}
@@ -0,0 +1,114 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+)
+
+func TestMillerLoopSplit(t *testing.T) {
+	e := NewEngine()
+	g1, g2 := e.G1, e.G2
+	n := 6
+	p1s, p2s := make([]*PointG1, n), make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, _ := new(Fr).Rand(rand.Reader)
+		p1s[i] = g1.MulScalar(g1.New(), g1.One(), a)
+		p2s[i] = g2.MulScalar(g2.New(), g2.One(), b)
+		e.AddPair(p1s[i], p2s[i])
+	}
+	expected := e.Result()
+
+	// partial results of disjoint sets of pairs from separate engines
+	partials := make([]*E, 0, 3)
+	for i := 0; i < n; i += 2 {
+		partial := NewEngine()
+		partial.AddPair(p1s[i], p2s[i])
+		partial.AddPairPrepared(p1s[i+1], partial.PrepareG2(p2s[i+1]))
+		f, err := e.MillerLoopFromBytes(e.MillerLoopToBytes(partial.MillerLoop()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		partials = append(partials, f)
+	}
+	f := e.MulMillerLoops(partials...)
+	copied := new(E).Set(f)
+	if !e.FinalExponentiate(f).Equal(expected) {
+		t.Fatal("final exponentiation of product of partial miller loops failed")
+	}
+	if !f.Equal(copied) {
+		t.Fatal("miller loop result is modified")
+	}
+	if !e.FinalExponentiate(e.MillerLoop()).IsOne() {
+		t.Fatal("empty miller loop must give one")
+	}
+	if _, err := e.MillerLoopFromBytes(make([]byte, 576)); err == nil {
+		t.Fatal("zero must be rejected")
+	}
+}
+
+func TestPairingParallel(t *testing.T) {
+	e := NewEngine()
+	g1, g2 := e.G1, e.G2
+	n := 9
+	p1s, p2s := make([]*PointG1, n), make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, _ := new(Fr).Rand(rand.Reader)
+		p1s[i] = g1.MulScalar(g1.New(), g1.One(), a)
+		p2s[i] = g2.MulScalar(g2.New(), g2.One(), b)
+	}
+	add := func(e *Engine) *Engine {
+		for i := 0; i < n; i++ {
+			if i%3 == 0 {
+				e.AddPairPrepared(p1s[i], e.PrepareG2(p2s[i]))
+			} else {
+				e.AddPair(p1s[i], p2s[i])
+			}
+		}
+		return e
+	}
+	expected := add(e).Result()
+	for _, workers := range []int{-1, 0, 1, 2, 4, n, 2 * n} {
+		if !add(e).ResultParallel(workers).Equal(expected) {
+			t.Fatalf("parallel pairing failed with %d workers", workers)
+		}
+		if !e.FinalExponentiate(add(e).MillerLoopParallel(workers)).Equal(expected) {
+			t.Fatalf("parallel miller loop failed with %d workers", workers)
+		}
+	}
+	if !e.CheckParallel(4) || !e.ResultParallel(4).IsOne() {
+		t.Fatal("empty parallel pairing must give one")
+	}
+	// e(a*G1, G2) * e(-G1, a*G2) == 1
+	a, _ := new(Fr).Rand(rand.Reader)
+	e.AddPair(g1.MulScalar(g1.New(), g1.One(), a), g2.One())
+	e.AddPairInv(g1.One(), g2.MulScalar(g2.New(), g2.One(), a))
+	if !e.CheckParallel(2) {
+		t.Fatal("parallel pairing check failed")
+	}
+}
+
+func BenchmarkPairingParallel(t *testing.B) {
+	e := NewEngine()
+	g1, g2 := e.G1, e.G2
+	n := 128
+	p1s, p2s := make([]*PointG1, n), make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		p1s[i] = g1.MulScalar(g1.New(), g1.One(), a)
+		p2s[i] = g2.MulScalar(g2.New(), g2.One(), a)
+	}
+	for _, workers := range []int{1, 0} {
+		t.Run(fmt.Sprintf("%d_workers_%d", n, workers), func(t *testing.B) {
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				for j := 0; j < n; j++ {
+					e.AddPair(p1s[j], p2s[j])
+				}
+				e.ResultParallel(workers)
+			}
+		})
+	}
+}
@@ -0,0 +1,551 @@
+package bls12381
+
+import (
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Multi exponentiation follows bucket method of Pippenger. Scalars are recoded into signed c bit digits
+// and for each window points are accumulated into 2^(c-1) buckets by the absolute values of their digits,
+// where points with negative digits are negated. Bucket i is then weighted by i+1 with running sums.
+//
+// For large inputs buckets are kept in affine form and additions into buckets are batched
+// so that a batch shares a single inversion with Montgomery's trick. Additions in a batch must hit
+// distinct buckets, so an addition to a bucket which already has a pending addition is queued
+// and scheduled into a later batch.
+//
+// In parallel mode work is split into jobs where each job is a window and a chunk of points.
+// Windows are independent so they are distributed first, and if there are more workers than
+// windows points are also split into chunks whose partial window sums are added afterwards.
+// Each worker uses its own group instance and buckets since groups hold temporary values.
+//
+// In complete mode, used by MultiExpComplete, buckets of all windows are kept in projective coordinates
+// and points are added with complete formulas, which need no checks for zero or equal points.
+
+// msmWindowSizes is window size in bits indexed by bit length of number of points.
+// Values are tuned with benchmarks of G1 multi exponentiation.
+var msmWindowSizes = [...]int{
+	3, 3, 3, 3, 3, 3, // up to 31 points
+	4, 5, 6, 7, 7, // up to 1023 points
+	8, 9, 10, 11, 11, // up to 2^15 - 1 points
+	12, 13, 14, 14, 15, // up to 2^20 - 1 points
+}
+
+// msmWindowSize returns window size in bits for multi exponentiation of n points.
+func msmWindowSize(n int) int {
+	l := bits.Len(uint(n))
+	if l >= len(msmWindowSizes) {
+		return msmWindowSizes[len(msmWindowSizes)-1]
+	}
+	return msmWindowSizes[l]
+}
+
+// msmSchedule distributes window and point chunk pairs of a multi exponentiation to workers.
+type msmSchedule struct {
+	n, c, windows, chunks, chunkSize, workers int
+}
+
+// newMSMSchedule returns schedule of multi exponentiation of n points with given number of workers.
+// Number of workers defaults to GOMAXPROCS if it is not positive.
+func newMSMSchedule(n, workers int) *msmSchedule {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	c := msmWindowSize(n)
+	windows := 255/c + 1
+	chunks := 1
+	if workers > windows && n > 1 {
+		chunks = (workers + windows - 1) / windows
+		if chunks > n {
+			chunks = n
+		}
+	}
+	chunkSize := (n + chunks - 1) / chunks
+	if jobs := windows * chunks; workers > jobs {
+		workers = jobs
+	}
+	return &msmSchedule{n, c, windows, chunks, chunkSize, workers}
+}
+
+// newMSMChunkSchedule returns schedule of a single window of n digits which are split into chunks for workers.
+func newMSMChunkSchedule(n, c, workers int) *msmSchedule {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return &msmSchedule{n, c, 1, workers, (n + workers - 1) / workers, workers}
+}
+
+// jobs returns number of jobs. Job i is the window i / chunks with the chunk i % chunks.
+func (s *msmSchedule) jobs() int {
+	return s.windows * s.chunks
+}
+
+// batchAffine returns whether points of the window are accumulated in affine form.
+// The last window is left to Jacobian accumulation if it has fewer bits, since
+// additions to its few buckets would mostly conflict.
+func (s *msmSchedule) batchAffine(window int) bool {
+	return s.n >= msmBatchAffineThreshold && 255-s.c*window >= s.c
+}
+
+// run calls setup once in each worker. setup returns the job function of the worker
+// which is called with window index and the range of points of the job.
+func (s *msmSchedule) run(setup func() func(job, window, from, to int)) {
+	work := func(next func() int) {
+		job := setup()
+		for i := next(); i < s.jobs(); i = next() {
+			from := (i % s.chunks) * s.chunkSize
+			to := from + s.chunkSize
+			if from > s.n {
+				from = s.n
+			}
+			if to > s.n {
+				to = s.n
+			}
+			job(i, i/s.chunks, from, to)
+		}
+	}
+	if s.workers <= 1 {
+		i := -1
+		work(func() int { i++; return i })
+		return
+	}
+	var counter int64 = -1
+	next := func() int { return int(atomic.AddInt64(&counter, 1)) }
+	var wg sync.WaitGroup
+	for w := 0; w < s.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work(next)
+		}()
+	}
+	wg.Wait()
+}
+
+// msmBatchAffineThreshold is the least number of points for which buckets are accumulated in affine form.
+const msmBatchAffineThreshold = 1024
+
+// msmBatchSize returns number of additions in a batch for window size c.
+// A batch should be large enough to amortize the inversion while conflicts are still rare.
+func msmBatchSize(c int) int {
+	size := 1 << uint(c-1)
+	if size > 256 {
+		return 256
+	}
+	return size
+}
+
+// msmSignedDigits recodes scalars into signed digits in range [-2^(c-1), 2^(c-1)] for given number of windows.
+// Digits of window j are placed at [j*n, (j+1)*n) where n is number of scalars.
+// A digit d is encoded as |d| << 1 | s where s is one for negative digits, so window size must not exceed 15 bits.
+// Number of windows must satisfy c * windows > 255 so that the last digit does not produce a carry.
+func msmSignedDigits(scalars []*Fr, c, windows int) []uint16 {
+	n := len(scalars)
+	digits := make([]uint16, n*windows)
+	mask := uint64(1)<<uint(c) - 1
+	half := uint64(1) << uint(c-1)
+	for i, s := range scalars {
+		carry := uint64(0)
+		for j := 0; j < windows; j++ {
+			d := s.sliceUint64(c*j)&mask + carry
+			carry = 0
+			if d > half {
+				d, carry = 1<<uint(c)-d, 1
+				if d != 0 {
+					digits[j*n+i] = uint16(d<<1 | 1)
+				}
+				continue
+			}
+			digits[j*n+i] = uint16(d << 1)
+		}
+	}
+	return digits
+}
+
+type msmQueuedG1 struct {
+	bucket int
+	point  PointG1
+}
+
+// bucketsG1 accumulates G1 points of a window into buckets.
+type bucketsG1 struct {
+	g         *G1
+	buckets   []PointG1
+	batchSize int
+	pending   []bool
+	batch     []int
+	points    []PointG1
+	denoms    []fe
+	queue     []msmQueuedG1
+	proj      []PointG1Proj
+	// projective is set if the last accumulation is done in projective buckets
+	projective bool
+}
+
+func newBucketsG1(g *G1, c int) *bucketsG1 {
+	size, batchSize := 1<<uint(c-1), msmBatchSize(c)
+	return &bucketsG1{
+		g:         g,
+		buckets:   make([]PointG1, size),
+		batchSize: batchSize,
+		pending:   make([]bool, size),
+		batch:     make([]int, 0, batchSize),
+		points:    make([]PointG1, 0, batchSize),
+		denoms:    make([]fe, batchSize),
+		proj:      make([]PointG1Proj, size),
+	}
+}
+
+// accumulate resets buckets and adds points to buckets by their encoded signed digits.
+// Points are expected to be in affine form. If batchAffine is not set points are added to buckets in Jacobian form.
+func (b *bucketsG1) accumulate(points []*PointG1, digits []uint16, batchAffine bool) {
+	g := b.g
+	b.projective = false
+	for i := range b.buckets {
+		b.buckets[i].Zero()
+	}
+	p := new(PointG1)
+	for i, d := range digits {
+		if d == 0 || g.IsZero(points[i]) {
+			continue
+		}
+		k := int(d>>1) - 1
+		if d&1 == 1 {
+			g.Neg(p, points[i])
+		} else {
+			p.Set(points[i])
+		}
+		if !batchAffine {
+			g.AddMixed(&b.buckets[k], &b.buckets[k], p)
+			continue
+		}
+		b.add(k, p)
+		if len(b.batch) == b.batchSize || len(b.queue) == 4*b.batchSize {
+			b.flush()
+			b.schedule()
+		}
+	}
+	b.flush()
+	b.schedule()
+	b.flush()
+	// remaining conflicts are too few to share an inversion
+	for i := range b.queue {
+		q := &b.queue[i]
+		g.AddMixed(&b.buckets[q.bucket], &b.buckets[q.bucket], &q.point)
+	}
+	b.queue = b.queue[:0]
+}
+
+// add adds p to the bucket k either directly if the bucket is empty or
+// as an addition in the batch. Conflicting additions are queued.
+func (b *bucketsG1) add(k int, p *PointG1) {
+	switch {
+	case b.pending[k]:
+		b.queue = append(b.queue, msmQueuedG1{k, *p})
+	case b.g.IsZero(&b.buckets[k]):
+		b.buckets[k].Set(p)
+	default:
+		b.pending[k] = true
+		b.batch = append(b.batch, k)
+		b.points = append(b.points, *p)
+	}
+}
+
+// schedule moves queued additions into the batch as long as they do not conflict and the batch is not full.
+func (b *bucketsG1) schedule() {
+	n := 0
+	for i := range b.queue {
+		q := &b.queue[i]
+		if b.pending[q.bucket] || len(b.batch) == b.batchSize {
+			b.queue[n] = *q
+			n++
+			continue
+		}
+		b.add(q.bucket, &q.point)
+	}
+	b.queue = b.queue[:n]
+}
+
+// flush applies additions of the batch in affine coordinates with a single batch inversion.
+func (b *bucketsG1) flush() {
+	t := b.g.t
+	for i, k := range b.batch {
+		p, q := &b.buckets[k], &b.points[i]
+		switch {
+		case !p[0].equal(&q[0]):
+			sub(&b.denoms[i], &q[0], &p[0])
+		case p[1].equal(&q[1]) && !p[1].isZero():
+			double(&b.denoms[i], &p[1])
+		default:
+			// p = -q
+			b.denoms[i].zero()
+		}
+	}
+	inverseBatch(b.denoms[:len(b.batch)])
+	for i, k := range b.batch {
+		p, q := &b.buckets[k], &b.points[i]
+		b.pending[k] = false
+		if b.denoms[i].isZero() {
+			p.Zero()
+			continue
+		}
+		if p[0].equal(&q[0]) {
+			// lambda = 3x^2 / 2y
+			square(t[0], &p[0])
+			double(t[1], t[0])
+			add(t[0], t[0], t[1])
+		} else {
+			// lambda = (y2 - y1) / (x2 - x1)
+			sub(t[0], &q[1], &p[1])
+		}
+		mul(t[0], t[0], &b.denoms[i])
+		square(t[1], t[0])
+		sub(t[1], t[1], &p[0])
+		sub(t[1], t[1], &q[0]) // x3 = lambda^2 - x1 - x2
+		sub(t[2], &p[0], t[1])
+		mul(t[2], t[2], t[0])
+		sub(&p[1], t[2], &p[1]) // y3 = lambda * (x1 - x3) - y1
+		p[0].set(t[1])
+	}
+	b.batch = b.batch[:0]
+	b.points = b.points[:0]
+}
+
+// accumulateProjective resets projective buckets and adds points to them with complete formulas.
+func (b *bucketsG1) accumulateProjective(points []*PointG1, digits []uint16) {
+	g := b.g
+	b.projective = true
+	for i := range b.proj {
+		b.proj[i].Zero()
+	}
+	p := new(PointG1)
+	for i, d := range digits {
+		if d == 0 {
+			continue
+		}
+		k := int(d>>1) - 1
+		p.Set(points[i])
+		if d&1 == 1 {
+			g.Neg(p, p)
+		}
+		g.AddMixedProjective(&b.proj[k], &b.proj[k], p)
+	}
+}
+
+// sum calculates sum of buckets weighted by their indexes and assigns it to r.
+func (b *bucketsG1) sum(r *PointG1) {
+	g := b.g
+	if b.projective {
+		acc, sum := new(PointG1Proj).Zero(), new(PointG1Proj).Zero()
+		for i := len(b.proj) - 1; i >= 0; i-- {
+			g.AddProjective(sum, sum, &b.proj[i])
+			g.AddProjective(acc, acc, sum)
+		}
+		g.FromProjective(r, acc)
+		return
+	}
+	acc, sum := g.New(), g.New()
+	for i := len(b.buckets) - 1; i >= 0; i-- {
+		g.Add(sum, sum, &b.buckets[i])
+		g.Add(acc, acc, sum)
+	}
+	r.Set(acc)
+}
+
+type msmQueuedG2 struct {
+	bucket int
+	point  PointG2
+}
+
+// bucketsG2 accumulates G2 points of a window into buckets.
+type bucketsG2 struct {
+	g         *G2
+	buckets   []PointG2
+	batchSize int
+	pending   []bool
+	batch     []int
+	points    []PointG2
+	denoms    []fe2
+	queue     []msmQueuedG2
+	proj      []PointG2Proj
+	// projective is set if the last accumulation is done in projective buckets
+	projective bool
+}
+
+func newBucketsG2(g *G2, c int) *bucketsG2 {
+	size, batchSize := 1<<uint(c-1), msmBatchSize(c)
+	return &bucketsG2{
+		g:         g,
+		buckets:   make([]PointG2, size),
+		batchSize: batchSize,
+		pending:   make([]bool, size),
+		batch:     make([]int, 0, batchSize),
+		points:    make([]PointG2, 0, batchSize),
+		denoms:    make([]fe2, batchSize),
+		proj:      make([]PointG2Proj, size),
+	}
+}
+
+// accumulate resets buckets and adds points to buckets by their encoded signed digits.
+// Points are expected to be in affine form. If batchAffine is not set points are added to buckets in Jacobian form.
+func (b *bucketsG2) accumulate(points []*PointG2, digits []uint16, batchAffine bool) {
+	g := b.g
+	b.projective = false
+	for i := range b.buckets {
+		b.buckets[i].Zero()
+	}
+	p := new(PointG2)
+	for i, d := range digits {
+		if d == 0 || g.IsZero(points[i]) {
+			continue
+		}
+		k := int(d>>1) - 1
+		if d&1 == 1 {
+			g.Neg(p, points[i])
+		} else {
+			p.Set(points[i])
+		}
+		if !batchAffine {
+			g.AddMixed(&b.buckets[k], &b.buckets[k], p)
+			continue
+		}
+		b.add(k, p)
+		if len(b.batch) == b.batchSize || len(b.queue) == 4*b.batchSize {
+			b.flush()
+			b.schedule()
+		}
+	}
+	b.flush()
+	b.schedule()
+	b.flush()
+	// remaining conflicts are too few to share an inversion
+	for i := range b.queue {
+		q := &b.queue[i]
+		g.AddMixed(&b.buckets[q.bucket], &b.buckets[q.bucket], &q.point)
+	}
+	b.queue = b.queue[:0]
+}
+
+// add adds p to the bucket k either directly if the bucket is empty or
+// as an addition in the batch. Conflicting additions are queued.
+func (b *bucketsG2) add(k int, p *PointG2) {
+	switch {
+	case b.pending[k]:
+		b.queue = append(b.queue, msmQueuedG2{k, *p})
+	case b.g.IsZero(&b.buckets[k]):
+		b.buckets[k].Set(p)
+	default:
+		b.pending[k] = true
+		b.batch = append(b.batch, k)
+		b.points = append(b.points, *p)
+	}
+}
+
+// schedule moves queued additions into the batch as long as they do not conflict and the batch is not full.
+func (b *bucketsG2) schedule() {
+	n := 0
+	for i := range b.queue {
+		q := &b.queue[i]
+		if b.pending[q.bucket] || len(b.batch) == b.batchSize {
+			b.queue[n] = *q
+			n++
+			continue
+		}
+		b.add(q.bucket, &q.point)
+	}
+	b.queue = b.queue[:n]
+}
+
+// flush applies additions of the batch in affine coordinates with a single batch inversion.
+func (b *bucketsG2) flush() {
+	e, t := b.g.f, b.g.t
+	for i, k := range b.batch {
+		p, q := &b.buckets[k], &b.points[i]
+		switch {
+		case !p[0].equal(&q[0]):
+			fp2Sub(&b.denoms[i], &q[0], &p[0])
+		case p[1].equal(&q[1]) && !p[1].isZero():
+			fp2Double(&b.denoms[i], &p[1])
+		default:
+			// p = -q
+			b.denoms[i].zero()
+		}
+	}
+	e.inverseBatch(b.denoms[:len(b.batch)])
+	for i, k := range b.batch {
+		p, q := &b.buckets[k], &b.points[i]
+		b.pending[k] = false
+		if b.denoms[i].isZero() {
+			p.Zero()
+			continue
+		}
+		if p[0].equal(&q[0]) {
+			// lambda = 3x^2 / 2y
+			e.square(t[0], &p[0])
+			fp2Double(t[1], t[0])
+			fp2Add(t[0], t[0], t[1])
+		} else {
+			// lambda = (y2 - y1) / (x2 - x1)
+			fp2Sub(t[0], &q[1], &p[1])
+		}
+		e.mul(t[0], t[0], &b.denoms[i])
+		e.square(t[1], t[0])
+		fp2Sub(t[1], t[1], &p[0])
+		fp2Sub(t[1], t[1], &q[0]) // x3 = lambda^2 - x1 - x2
+		fp2Sub(t[2], &p[0], t[1])
+		e.mul(t[2], t[2], t[0])
+		fp2Sub(&p[1], t[2], &p[1]) // y3 = lambda * (x1 - x3) - y1
+		p[0].set(t[1])
+	}
+	b.batch = b.batch[:0]
+	b.points = b.points[:0]
+}
+
+// accumulateProjective resets projective buckets and adds points to them with complete formulas.
+func (b *bucketsG2) accumulateProjective(points []*PointG2, digits []uint16) {
+	g := b.g
+	b.projective = true
+	for i := range b.proj {
+		b.proj[i].Zero()
+	}
+	p := new(PointG2)
+	for i, d := range digits {
+		if d == 0 {
+			continue
+		}
+		k := int(d>>1) - 1
+		p.Set(points[i])
+		if d&1 == 1 {
+			g.Neg(p, p)
+		}
+		g.AddMixedProjective(&b.proj[k], &b.proj[k], p)
+	}
+}
+
+// sum calculates sum of buckets weighted by their indexes and assigns it to r.
+func (b *bucketsG2) sum(r *PointG2) {
+	g := b.g
+	if b.projective {
+		acc, sum := new(PointG2Proj).Zero(), new(PointG2Proj).Zero()
+		for i := len(b.proj) - 1; i >= 0; i-- {
+			g.AddProjective(sum, sum, &b.proj[i])
+			g.AddProjective(acc, acc, sum)
+		}
+		g.FromProjective(r, acc)
+		return
+	}
+	acc, sum := g.New(), g.New()
+	for i := len(b.buckets) - 1; i >= 0; i-- {
+		g.Add(sum, sum, &b.buckets[i])
+		g.Add(acc, acc, sum)
+	}
+	r.Set(acc)
+}
@@ -0,0 +1,280 @@
+package bls12381
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Multi exponentiation tables are for bases which are used in many multi exponentiations
+// such as structured reference strings of polynomial commitments.
+// For each base P a table holds 2^(c*j) * P for all windows j in affine form, so that
+// all windows of a multi exponentiation are accumulated into a single set of buckets.
+// It removes the doublings and the bucket reductions of all windows but one and
+// allows wider windows than regular multi exponentiation, which also keeps the table smaller.
+//
+// Tables are encoded as window size in a byte, number of bases in four bytes big endian and
+// the points of the table in uncompressed form window by window. Decoding checks that points
+// are on curve but neither subgroup membership nor consistency with bases is checked,
+// so encoded tables are expected to be kept in trusted storage.
+
+const multiExpTableHeaderSize = 5
+
+var errMultiExpTableEncoding = errors.New("bad multi exponentiation table encoding")
+
+// multiExpTableWindowSize returns window size which minimizes the number of additions
+// to buckets and of the bucket reduction for n bases.
+func multiExpTableWindowSize(n int) int {
+	c, cost := 2, -1
+	for i := 2; i <= 15; i++ {
+		if v := n*(255/i+1) + 1<<uint(i); cost < 0 || v < cost {
+			c, cost = i, v
+		}
+	}
+	return c
+}
+
+// MultiExpTableG1 is precomputed table of G1 bases for multi exponentiation.
+// A table is not modified after it is built so it can be used concurrently.
+type MultiExpTableG1 struct {
+	c, n   int
+	points []*PointG1
+}
+
+// NewMultiExpTable builds multi exponentiation table of given bases.
+func (g *G1) NewMultiExpTable(bases []*PointG1) *MultiExpTableG1 {
+	n := len(bases)
+	c := multiExpTableWindowSize(n)
+	t := newMultiExpTableG1(c, n)
+	runConcurrent(n, func(from, to int) {
+		g := NewG1()
+		window := make([]*PointG1, to-from)
+		for i := from; i < to; i++ {
+			window[i-from] = t.points[i].Set(bases[i])
+		}
+		g.AffineBatch(window)
+		for j := 1; j < t.windows(); j++ {
+			for i := from; i < to; i++ {
+				p := t.points[j*n+i].Set(window[i-from])
+				for k := 0; k < c; k++ {
+					g.Double(p, p)
+				}
+				window[i-from] = p
+			}
+			g.AffineBatch(window)
+		}
+	})
+	return t
+}
+
+func newMultiExpTableG1(c, n int) *MultiExpTableG1 {
+	t := &MultiExpTableG1{c: c, n: n}
+	points := make([]PointG1, n*t.windows())
+	t.points = make([]*PointG1, len(points))
+	for i := range points {
+		t.points[i] = &points[i]
+	}
+	return t
+}
+
+func (t *MultiExpTableG1) windows() int {
+	return 255/t.c + 1
+}
+
+// Len returns number of bases of the table.
+func (t *MultiExpTableG1) Len() int {
+	return t.n
+}
+
+// MultiExp calculates multi exponentiation of bases of the table with given scalars using given number of workers.
+// If number of workers is not positive GOMAXPROCS is used. Number of scalars is expected to be
+// equal to number of bases, otherwise an error is returned. Result is assigned to point at first argument.
+func (t *MultiExpTableG1) MultiExp(r *PointG1, scalars []*Fr, workers int) (*PointG1, error) {
+	if len(scalars) != t.n {
+		return nil, errors.New("number of scalars should be equal to number of bases")
+	}
+	digits := msmSignedDigits(scalars, t.c, t.windows())
+	s := newMSMChunkSchedule(len(digits), t.c, workers)
+	partials := make([]PointG1, s.jobs())
+	s.run(func() func(job, window, from, to int) {
+		b := newBucketsG1(NewG1(), t.c)
+		return func(job, window, from, to int) {
+			b.accumulate(t.points[from:to], digits[from:to], s.batchAffine(window))
+			b.sum(&partials[job])
+		}
+	})
+	g := NewG1()
+	acc := g.New()
+	for i := range partials {
+		g.Add(acc, acc, &partials[i])
+	}
+	return r.Set(acc), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (t *MultiExpTableG1) MarshalBinary() ([]byte, error) {
+	g := NewG1()
+	size := 2 * fpByteSize
+	out := make([]byte, multiExpTableHeaderSize+len(t.points)*size)
+	out[0] = byte(t.c)
+	binary.BigEndian.PutUint32(out[1:], uint32(t.n))
+	for i, p := range t.points {
+		copy(out[multiExpTableHeaderSize+i*size:], g.ToUncompressed(p))
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (t *MultiExpTableG1) UnmarshalBinary(data []byte) error {
+	if len(data) < multiExpTableHeaderSize || data[0] < 2 || data[0] > 15 {
+		return errMultiExpTableEncoding
+	}
+	c, n := int(data[0]), int(binary.BigEndian.Uint32(data[1:]))
+	size := 2 * fpByteSize
+	if uint64(len(data)-multiExpTableHeaderSize) != uint64(n)*uint64(255/c+1)*uint64(size) {
+		return errMultiExpTableEncoding
+	}
+	r := newMultiExpTableG1(c, n)
+	errs := make([]error, len(r.points))
+	runConcurrent(len(r.points), func(from, to int) {
+		g := NewG1()
+		for i := from; i < to; i++ {
+			in := data[multiExpTableHeaderSize+i*size : multiExpTableHeaderSize+(i+1)*size]
+			p, err := g.DecodeUncompressed(in, DecodeNoSubgroupCheck)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			r.points[i].Set(p)
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	*t = *r
+	return nil
+}
+
+// MultiExpTableG2 is precomputed table of G2 bases for multi exponentiation.
+// A table is not modified after it is built so it can be used concurrently.
+type MultiExpTableG2 struct {
+	c, n   int
+	points []*PointG2
+}
+
+// NewMultiExpTable builds multi exponentiation table of given bases.
+func (g *G2) NewMultiExpTable(bases []*PointG2) *MultiExpTableG2 {
+	n := len(bases)
+	c := multiExpTableWindowSize(n)
+	t := newMultiExpTableG2(c, n)
+	runConcurrent(n, func(from, to int) {
+		g := NewG2()
+		window := make([]*PointG2, to-from)
+		for i := from; i < to; i++ {
+			window[i-from] = t.points[i].Set(bases[i])
+		}
+		g.AffineBatch(window)
+		for j := 1; j < t.windows(); j++ {
+			for i := from; i < to; i++ {
+				p := t.points[j*n+i].Set(window[i-from])
+				for k := 0; k < c; k++ {
+					g.Double(p, p)
+				}
+				window[i-from] = p
+			}
+			g.AffineBatch(window)
+		}
+	})
+	return t
+}
+
+func newMultiExpTableG2(c, n int) *MultiExpTableG2 {
+	t := &MultiExpTableG2{c: c, n: n}
+	points := make([]PointG2, n*t.windows())
+	t.points = make([]*PointG2, len(points))
+	for i := range points {
+		t.points[i] = &points[i]
+	}
+	return t
+}
+
+func (t *MultiExpTableG2) windows() int {
+	return 255/t.c + 1
+}
+
+// Len returns number of bases of the table.
+func (t *MultiExpTableG2) Len() int {
+	return t.n
+}
+
+// MultiExp calculates multi exponentiation of bases of the table with given scalars using given number of workers.
+// If number of workers is not positive GOMAXPROCS is used. Number of scalars is expected to be
+// equal to number of bases, otherwise an error is returned. Result is assigned to point at first argument.
+func (t *MultiExpTableG2) MultiExp(r *PointG2, scalars []*Fr, workers int) (*PointG2, error) {
+	if len(scalars) != t.n {
+		return nil, errors.New("number of scalars should be equal to number of bases")
+	}
+	digits := msmSignedDigits(scalars, t.c, t.windows())
+	s := newMSMChunkSchedule(len(digits), t.c, workers)
+	partials := make([]PointG2, s.jobs())
+	s.run(func() func(job, window, from, to int) {
+		b := newBucketsG2(NewG2(), t.c)
+		return func(job, window, from, to int) {
+			b.accumulate(t.points[from:to], digits[from:to], s.batchAffine(window))
+			b.sum(&partials[job])
+		}
+	})
+	g := NewG2()
+	acc := g.New()
+	for i := range partials {
+		g.Add(acc, acc, &partials[i])
+	}
+	return r.Set(acc), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (t *MultiExpTableG2) MarshalBinary() ([]byte, error) {
+	g := NewG2()
+	size := 4 * fpByteSize
+	out := make([]byte, multiExpTableHeaderSize+len(t.points)*size)
+	out[0] = byte(t.c)
+	binary.BigEndian.PutUint32(out[1:], uint32(t.n))
+	for i, p := range t.points {
+		copy(out[multiExpTableHeaderSize+i*size:], g.ToUncompressed(p))
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (t *MultiExpTableG2) UnmarshalBinary(data []byte) error {
+	if len(data) < multiExpTableHeaderSize || data[0] < 2 || data[0] > 15 {
+		return errMultiExpTableEncoding
+	}
+	c, n := int(data[0]), int(binary.BigEndian.Uint32(data[1:]))
+	size := 4 * fpByteSize
+	if uint64(len(data)-multiExpTableHeaderSize) != uint64(n)*uint64(255/c+1)*uint64(size) {
+		return errMultiExpTableEncoding
+	}
+	r := newMultiExpTableG2(c, n)
+	errs := make([]error, len(r.points))
+	runConcurrent(len(r.points), func(from, to int) {
+		g := NewG2()
+		for i := from; i < to; i++ {
+			in := data[multiExpTableHeaderSize+i*size : multiExpTableHeaderSize+(i+1)*size]
+			p, err := g.DecodeUncompressed(in, DecodeNoSubgroupCheck)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			r.points[i].Set(p)
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	*t = *r
+	return nil
+}
@@ -0,0 +1,155 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestG1MultiExpTable(t *testing.T) {
+	g := NewG1()
+	for _, n := range []int{0, 1, 5, 100, 1500} {
+		bases := make([]*PointG1, n)
+		scalars := make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			bases[i] = g.rand()
+		}
+		if n > 2 {
+			bases[1] = g.Zero()
+			scalars[2] = new(Fr)
+		}
+		expected := g.New()
+		_, _ = g.MultiExp(expected, bases, scalars)
+		table := g.NewMultiExpTable(bases)
+		if table.Len() != n {
+			t.Fatal("bad table length")
+		}
+		for _, workers := range []int{0, 1, 3} {
+			result := g.New()
+			if _, err := table.MultiExp(result, scalars, workers); err != nil {
+				t.Fatal(err)
+			}
+			if !g.Equal(expected, result) {
+				t.Fatal("multi exponentiation with table failed", n, workers)
+			}
+		}
+	}
+	if _, err := g.NewMultiExpTable([]*PointG1{g.one()}).MultiExp(g.New(), []*Fr{}, 1); err == nil {
+		t.Fatal("length mismatch must be rejected")
+	}
+}
+
+func TestG2MultiExpTable(t *testing.T) {
+	g := NewG2()
+	for _, n := range []int{0, 1, 5, 100, 1500} {
+		bases := make([]*PointG2, n)
+		scalars := make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			bases[i] = g.rand()
+		}
+		if n > 2 {
+			bases[1] = g.Zero()
+			scalars[2] = new(Fr)
+		}
+		expected := g.New()
+		_, _ = g.MultiExp(expected, bases, scalars)
+		table := g.NewMultiExpTable(bases)
+		for _, workers := range []int{0, 1, 3} {
+			result := g.New()
+			if _, err := table.MultiExp(result, scalars, workers); err != nil {
+				t.Fatal(err)
+			}
+			if !g.Equal(expected, result) {
+				t.Fatal("multi exponentiation with table failed", n, workers)
+			}
+		}
+	}
+}
+
+func TestMultiExpTableSerialization(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	n := 20
+	bases1, bases2 := make([]*PointG1, n), make([]*PointG2, n)
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		scalars[i], _ = new(Fr).Rand(rand.Reader)
+		bases1[i], bases2[i] = g1.randCorrect(), g2.randCorrect()
+	}
+	bases1[3], bases2[3] = g1.Zero(), g2.Zero()
+	{
+		data, err := g1.NewMultiExpTable(bases1).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		table := new(MultiExpTableG1)
+		if err := table.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		expected, result := g1.New(), g1.New()
+		_, _ = g1.MultiExp(expected, bases1, scalars)
+		_, _ = table.MultiExp(result, scalars, 0)
+		if !g1.Equal(expected, result) {
+			t.Fatal("decoded table gives bad result")
+		}
+		if err := table.UnmarshalBinary(data[:len(data)-1]); err != errMultiExpTableEncoding {
+			t.Fatal("bad length must be rejected")
+		}
+		corrupted := append([]byte{}, data...)
+		corrupted[0] = 16
+		if err := table.UnmarshalBinary(corrupted); err != errMultiExpTableEncoding {
+			t.Fatal("bad window size must be rejected")
+		}
+		corrupted[0] = data[0]
+		corrupted[len(data)-1] ^= 1
+		if err := table.UnmarshalBinary(corrupted); !errors.Is(err, ErrNotOnCurve) {
+			t.Fatal("point not on curve must be rejected")
+		}
+	}
+	{
+		data, err := g2.NewMultiExpTable(bases2).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		table := new(MultiExpTableG2)
+		if err := table.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		expected, result := g2.New(), g2.New()
+		_, _ = g2.MultiExp(expected, bases2, scalars)
+		_, _ = table.MultiExp(result, scalars, 0)
+		if !g2.Equal(expected, result) {
+			t.Fatal("decoded table gives bad result")
+		}
+		if err := table.UnmarshalBinary(data[1:]); err != errMultiExpTableEncoding {
+			t.Fatal("bad encoding must be rejected")
+		}
+		corrupted := append([]byte{}, data...)
+		corrupted[len(data)-1] ^= 1
+		if err := table.UnmarshalBinary(corrupted); !errors.Is(err, ErrNotOnCurve) {
+			t.Fatal("point not on curve must be rejected")
+		}
+	}
+}
+
+func BenchmarkG1MultiExpTable(t *testing.B) {
+	g := NewG1()
+	for _, n := range []int{1 << 8, 1 << 12} {
+		bases := make([]*PointG1, n)
+		scalars := make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			bases[i] = g.randAffine()
+		}
+		table := g.NewMultiExpTable(bases)
+		t.Run(fmt.Sprint(n), func(t *testing.B) {
+			result := g.New()
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				_, _ = table.MultiExp(result, scalars, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestMSMSignedDigits(t *testing.T) {
+	n := 10
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		scalars[i], _ = new(Fr).Rand(rand.Reader)
+	}
+	scalars[0] = new(Fr)
+	scalars[1] = new(Fr).Set(&q)
+	scalars[1][0]--
+	for c := 2; c <= 15; c++ {
+		windows := 255/c + 1
+		digits := msmSignedDigits(scalars, c, windows)
+		for i, s := range scalars {
+			acc := new(big.Int)
+			for j := windows - 1; j >= 0; j-- {
+				d := digits[j*n+i]
+				if d>>1 > 1<<uint(c-1) {
+					t.Fatal("digit is out of range")
+				}
+				v := big.NewInt(int64(d >> 1))
+				if d&1 == 1 {
+					v.Neg(v)
+				}
+				acc.Lsh(acc, uint(c))
+				acc.Add(acc, v)
+			}
+			if acc.Cmp(s.ToBig()) != 0 {
+				t.Fatal("bad signed digit recoding", c)
+			}
+		}
+	}
+}
+
+func TestG1MultiExpBatchAffine(t *testing.T) {
+	g := NewG1()
+	// repeated points and scalars lead to doublings and cancellations in buckets
+	p0, p1 := g.randAffine(), g.randAffine()
+	p2 := g.Neg(g.New(), p0)
+	s0, _ := new(Fr).Rand(rand.Reader)
+	s1, _ := new(Fr).Rand(rand.Reader)
+	n := 2 * msmBatchAffineThreshold
+	bases := make([]*PointG1, n)
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		bases[i] = new(PointG1).Set([]*PointG1{p0, p1, p2, g.Zero()}[i%4])
+		scalars[i] = new(Fr).Set([]*Fr{s0, s1}[(i/4)%2])
+		if i%7 == 0 {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+		}
+	}
+	expected, tmp := g.New(), g.New()
+	for i := 0; i < n; i++ {
+		g.mulScalar(tmp, bases[i], scalars[i])
+		g.Add(expected, expected, tmp)
+	}
+	for _, workers := range []int{1, 3} {
+		result := g.New()
+		_, _ = g.MultiExpParallel(result, bases, scalars, workers)
+		if !g.Equal(expected, result) {
+			t.Fatal("multi-exponentiation failed", workers)
+		}
+	}
+}
+
+func TestG2MultiExpBatchAffine(t *testing.T) {
+	g := NewG2()
+	p0, p1 := g.randAffine(), g.randAffine()
+	p2 := g.Neg(g.New(), p0)
+	s0, _ := new(Fr).Rand(rand.Reader)
+	s1, _ := new(Fr).Rand(rand.Reader)
+	n := 2 * msmBatchAffineThreshold
+	bases := make([]*PointG2, n)
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		bases[i] = new(PointG2).Set([]*PointG2{p0, p1, p2, g.Zero()}[i%4])
+		scalars[i] = new(Fr).Set([]*Fr{s0, s1}[(i/4)%2])
+		if i%7 == 0 {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+		}
+	}
+	expected, tmp := g.New(), g.New()
+	for i := 0; i < n; i++ {
+		g.mulScalar(tmp, bases[i], scalars[i])
+		g.Add(expected, expected, tmp)
+	}
+	for _, workers := range []int{1, 3} {
+		result := g.New()
+		_, _ = g.MultiExpParallel(result, bases, scalars, workers)
+		if !g.Equal(expected, result) {
+			t.Fatal("multi-exponentiation failed", workers)
+		}
+	}
+}
+
+func TestMultiExpComplete(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	for _, n := range []int{1, 50, msmBatchAffineThreshold} {
+		bases1, bases2 := make([]*PointG1, n), make([]*PointG2, n)
+		scalars := make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			bases1[i], bases2[i] = g1.randAffine(), g2.randAffine()
+		}
+		if n > 3 {
+			bases1[1], bases2[1] = g1.Zero(), g2.Zero()
+			bases1[2], bases2[2] = new(PointG1).Set(bases1[0]), new(PointG2).Set(bases2[0])
+			bases1[3], bases2[3] = g1.Neg(g1.New(), bases1[0]), g2.Neg(g2.New(), bases2[0])
+			scalars[2], scalars[3] = new(Fr).Set(scalars[0]), new(Fr).Set(scalars[0])
+		}
+		expected1, expected2, tmp1, tmp2 := g1.New(), g2.New(), g1.New(), g2.New()
+		for i := 0; i < n; i++ {
+			g1.Add(expected1, expected1, g1.mulScalar(tmp1, bases1[i], scalars[i]))
+			g2.Add(expected2, expected2, g2.mulScalar(tmp2, bases2[i], scalars[i]))
+		}
+		for _, workers := range []int{1, 3} {
+			result1, result2 := g1.New(), g2.New()
+			_, _ = g1.MultiExpComplete(result1, bases1, scalars, workers)
+			_, _ = g2.MultiExpComplete(result2, bases2, scalars, workers)
+			if !g1.Equal(expected1, result1) || !g2.Equal(expected2, result2) {
+				t.Fatal("multi exponentiation with complete buckets failed", n, workers)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package bls12381
+
+import "sync"
+
+// Multiplication of generators uses fixed window method with precomputed tables.
+// Scalars are recoded into signed digits of baseWindowSize bits and the table holds
+// d * 2^(w*j) * G for d = 1, ..., 2^(w-1) for each window j in affine form,
+// so a multiplication takes a mixed addition per window and no doublings.
+// Tables are built at the first use.
+//
+// In constant time mode recoding is branchless, each lookup scans the whole row of the window
+// with conditional moves and additions handle zero and equal inputs with conditional moves.
+
+const (
+	baseWindowSize = 6
+	baseWindows    = 255/baseWindowSize + 1
+	baseRowSize    = 1 << (baseWindowSize - 1)
+)
+
+var baseTableG1 struct {
+	once sync.Once
+	rows [baseWindows][baseRowSize]PointG1
+}
+
+var baseTableG2 struct {
+	once sync.Once
+	rows [baseWindows][baseRowSize]PointG2
+}
+
+func initBaseTableG1() {
+	g := NewG1()
+	p := g.One()
+	points := make([]*PointG1, 0, baseWindows*baseRowSize)
+	for j := 0; j < baseWindows; j++ {
+		row := &baseTableG1.rows[j]
+		row[0].Set(p)
+		for d := 1; d < baseRowSize; d++ {
+			g.Add(&row[d], &row[d-1], p)
+		}
+		g.Double(p, &row[baseRowSize-1])
+		for d := range row {
+			points = append(points, &row[d])
+		}
+	}
+	g.AffineBatch(points)
+}
+
+func initBaseTableG2() {
+	g := NewG2()
+	p := g.One()
+	points := make([]*PointG2, 0, baseWindows*baseRowSize)
+	for j := 0; j < baseWindows; j++ {
+		row := &baseTableG2.rows[j]
+		row[0].Set(p)
+		for d := 1; d < baseRowSize; d++ {
+			g.Add(&row[d], &row[d-1], p)
+		}
+		g.Double(p, &row[baseRowSize-1])
+		for d := range row {
+			points = append(points, &row[d])
+		}
+	}
+	g.AffineBatch(points)
+}
+
+// MulBase multiplies the generator of G1 by given scalar and assigns the result to point at first argument.
+func (g *G1) MulBase(r *PointG1, e *Fr) *PointG1 {
+	baseTableG1.once.Do(initBaseTableG1)
+	digits := msmSignedDigits([]*Fr{e}, baseWindowSize, baseWindows)
+	acc, q := g.New(), g.New()
+	for j, d := range digits {
+		if d == 0 {
+			continue
+		}
+		p := &baseTableG1.rows[j][d>>1-1]
+		if d&1 == 1 {
+			p = g.Neg(q, p)
+		}
+		g.AddMixed(acc, acc, p)
+	}
+	return r.Set(acc)
+}
+
+// MulBaseCT is constant time version of MulBase for secret scalars such as private keys.
+func (g *G1) MulBaseCT(r *PointG1, e *Fr) *PointG1 {
+	baseTableG1.once.Do(initBaseTableG1)
+	digits, signs := ctSignedDigits(e, baseWindowSize, baseWindows)
+	acc, sum, q := g.New(), g.New(), g.New()
+	y := new(fe)
+	for j := 0; j < baseWindows; j++ {
+		row := &baseTableG1.rows[j]
+		q.Set(&row[0])
+		for d := 1; d < baseRowSize; d++ {
+			q.cmov(&row[d], ctEqualUint64(digits[j], uint64(d+1)))
+		}
+		ctNeg(y, &q[1])
+		q[1].cmov(y, signs[j])
+		g.addMixedCT(sum, acc, q)
+		acc.cmov(sum, ctEqualUint64(digits[j], 0)^1)
+	}
+	return r.Set(acc)
+}
+
+// MulBase multiplies the generator of G2 by given scalar and assigns the result to point at first argument.
+func (g *G2) MulBase(r *PointG2, e *Fr) *PointG2 {
+	baseTableG2.once.Do(initBaseTableG2)
+	digits := msmSignedDigits([]*Fr{e}, baseWindowSize, baseWindows)
+	acc, q := g.New(), g.New()
+	for j, d := range digits {
+		if d == 0 {
+			continue
+		}
+		p := &baseTableG2.rows[j][d>>1-1]
+		if d&1 == 1 {
+			p = g.Neg(q, p)
+		}
+		g.AddMixed(acc, acc, p)
+	}
+	return r.Set(acc)
+}
+
+// MulBaseCT is constant time version of MulBase for secret scalars such as private keys.
+func (g *G2) MulBaseCT(r *PointG2, e *Fr) *PointG2 {
+	baseTableG2.once.Do(initBaseTableG2)
+	digits, signs := ctSignedDigits(e, baseWindowSize, baseWindows)
+	acc, sum, q := g.New(), g.New(), g.New()
+	y := new(fe2)
+	for j := 0; j < baseWindows; j++ {
+		row := &baseTableG2.rows[j]
+		q.Set(&row[0])
+		for d := 1; d < baseRowSize; d++ {
+			q.cmov(&row[d], ctEqualUint64(digits[j], uint64(d+1)))
+		}
+		fp2NegCT(y, &q[1])
+		q[1].cmov(y, signs[j])
+		g.addMixedCT(sum, acc, q)
+		acc.cmov(sum, ctEqualUint64(digits[j], 0)^1)
+	}
+	return r.Set(acc)
+}
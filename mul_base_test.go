@@ -0,0 +1,166 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// mulBaseTestScalars returns random scalars along with edge cases of signed digit recoding.
+func mulBaseTestScalars() []*Fr {
+	scalars := []*Fr{
+		new(Fr),
+		new(Fr).setUint64(1),
+		new(Fr).setUint64(baseRowSize),
+		new(Fr).setUint64(baseRowSize + 1),
+		new(Fr).setUint64(1<<baseWindowSize - 1),
+		new(Fr).Set(&q),
+	}
+	scalars[len(scalars)-1][0]--
+	// 2^254 + 2^253 + ... + 1 in steps of window size has all digits equal to half
+	s := new(big.Int)
+	for j := 0; j < baseWindows-1; j++ {
+		s.Add(s, new(big.Int).Lsh(big.NewInt(baseRowSize), uint(baseWindowSize*j)))
+	}
+	scalars = append(scalars, new(Fr).FromBytes(s.Mod(s, qBig).Bytes()))
+	for i := 0; i < fuz; i++ {
+		e, _ := new(Fr).Rand(rand.Reader)
+		scalars = append(scalars, e)
+	}
+	return scalars
+}
+
+func TestG1MulBase(t *testing.T) {
+	g := NewG1()
+	for i, e := range mulBaseTestScalars() {
+		expected, r0, r1 := g.New(), g.New(), g.New()
+		g.mulScalar(expected, g.One(), e)
+		g.MulBase(r0, e)
+		g.MulBaseCT(r1, e)
+		if !g.Equal(expected, r0) {
+			t.Fatal("multiplication of generator failed", i)
+		}
+		if !g.Equal(expected, r1) {
+			t.Fatal("constant time multiplication of generator failed", i)
+		}
+	}
+}
+
+func TestG2MulBase(t *testing.T) {
+	g := NewG2()
+	for i, e := range mulBaseTestScalars() {
+		expected, r0, r1 := g.New(), g.New(), g.New()
+		g.mulScalar(expected, g.One(), e)
+		g.MulBase(r0, e)
+		g.MulBaseCT(r1, e)
+		if !g.Equal(expected, r0) {
+			t.Fatal("multiplication of generator failed", i)
+		}
+		if !g.Equal(expected, r1) {
+			t.Fatal("constant time multiplication of generator failed", i)
+		}
+	}
+}
+
+func TestAddMixedCT(t *testing.T) {
+	g1 := NewG1()
+	for i := 0; i < fuz; i++ {
+		p, q := g1.rand(), g1.randAffine()
+		for _, a := range []*PointG1{p, g1.Zero(), new(PointG1).Set(q), g1.Neg(g1.New(), q)} {
+			expected, r := g1.New(), g1.New()
+			g1.AddMixed(expected, a, q)
+			g1.addMixedCT(r, a, q)
+			if !g1.Equal(expected, r) {
+				t.Fatal("constant time mixed addition failed")
+			}
+		}
+	}
+	g2 := NewG2()
+	for i := 0; i < fuz; i++ {
+		p, q := g2.rand(), g2.randAffine()
+		for _, a := range []*PointG2{p, g2.Zero(), new(PointG2).Set(q), g2.Neg(g2.New(), q)} {
+			expected, r := g2.New(), g2.New()
+			g2.AddMixed(expected, a, q)
+			g2.addMixedCT(r, a, q)
+			if !g2.Equal(expected, r) {
+				t.Fatal("constant time mixed addition failed")
+			}
+		}
+	}
+}
+
+func TestDudectG1MulBaseCT(t *testing.T) {
+	g := NewG1()
+	fixed := func() []byte { return make([]byte, 32) }
+	random := func() []byte {
+		e, _ := new(Fr).Rand(rand.Reader)
+		return e.ToBytes()
+	}
+	r := g.New()
+	dudectRun(t, "G1 MulBaseCT", fixed, random, func(in []byte) { g.MulBaseCT(r, new(Fr).FromBytes(in)) })
+}
+
+func TestDudectG2MulBaseCT(t *testing.T) {
+	g := NewG2()
+	fixed := func() []byte { return make([]byte, 32) }
+	random := func() []byte {
+		e, _ := new(Fr).Rand(rand.Reader)
+		return e.ToBytes()
+	}
+	r := g.New()
+	dudectRun(t, "G2 MulBaseCT", fixed, random, func(in []byte) { g.MulBaseCT(r, new(Fr).FromBytes(in)) })
+}
+
+func BenchmarkG1MulBase(t *testing.B) {
+	g := NewG1()
+	e, _ := new(Fr).Rand(rand.Reader)
+	r := g.New()
+	g.MulBase(r, e)
+	t.Run("MulScalar", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulScalar(r, g.One(), e)
+		}
+	})
+	t.Run("MulBase", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulBase(r, e)
+		}
+	})
+	t.Run("MulBaseCT", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulBaseCT(r, e)
+		}
+	})
+	t.Run("MulScalarCT", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulScalarCT(r, g.One(), e)
+		}
+	})
+}
+
+func BenchmarkG2MulBase(t *testing.B) {
+	g := NewG2()
+	e, _ := new(Fr).Rand(rand.Reader)
+	r := g.New()
+	g.MulBase(r, e)
+	t.Run("MulScalar", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulScalar(r, g.One(), e)
+		}
+	})
+	t.Run("MulBase", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulBase(r, e)
+		}
+	})
+	t.Run("MulBaseCT", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulBaseCT(r, e)
+		}
+	})
+	t.Run("MulScalarCT", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulScalarCT(r, g.One(), e)
+		}
+	})
+}
@@ -1,12 +1,15 @@
 package bls12381
 
+import "errors"
+
 type pair struct {
-	g1 *PointG1
-	g2 *PointG2
+	g1       *PointG1
+	g2       *PointG2
+	prepared *PreparedG2
 }
 
 func newPair(g1 *PointG1, g2 *PointG2) pair {
-	return pair{g1, g2}
+	return pair{g1: g1, g2: g2}
 }
 
 // Engine is BLS12-381 elliptic curve pairing engine
@@ -49,12 +52,20 @@ func newEngineTemp() pairingEngineTemp {
 	return pairingEngineTemp{t2, t12}
 }
 
-// AddPair adds a g1, g2 point pair to pairing engine
+// AddPair adds a g1, g2 point pair to pairing engine.
+// Points are copied in affine form so they are not modified and can be reused after the call.
 func (e *Engine) AddPair(g1 *PointG1, g2 *PointG2) *Engine {
-	p := newPair(g1, g2)
-	if !(e.G1.IsZero(p.g1) || e.G2.IsZero(p.g2)) {
-		e.G1.Affine(p.g1)
-		e.G2.Affine(p.g2)
+	if !(e.G1.IsZero(g1) || e.G2.IsZero(g2)) {
+		p := newPair(e.G1.affine(new(PointG1), g1), e.G2.affine(new(PointG2), g2))
+		e.pairs = append(e.pairs, p)
+	}
+	return e
+}
+
+// AddPairAffine adds a g1, g2 point pair in affine coordinates to pairing engine.
+func (e *Engine) AddPairAffine(g1 *G1Affine, g2 *G2Affine) *Engine {
+	if !(g1.IsZero() || g2.IsZero()) {
+		p := newPair(e.G1.FromAffine(new(PointG1), g1), e.G2.FromAffine(new(PointG2), g2))
 		e.pairs = append(e.pairs, p)
 	}
 	return e
@@ -74,7 +85,11 @@ func (e *Engine) Reset() *Engine {
 	return e
 }
 
-func (e *Engine) double(f *fe12, r *PointG2, k int) {
+// line holds coefficients of a line function in Miller loop before evaluation at a G1 point.
+type line [3]fe2
+
+// double doubles r and assigns coefficients of the tangent line at r to l.
+func (e *Engine) double(l *line, r *PointG2) {
 	fp2, t := e.fp2, e.t2
 
 	fp2.mul(t[0], &r[0], &r[1])
@@ -93,7 +108,7 @@ func (e *Engine) double(f *fe12, r *PointG2, k int) {
 	fp2Add(t[7], t[2], t[1])
 	fp2SubAssign(t[6], t[7])
 
-	fp2Sub(t[8], t[3], t[1])
+	fp2Sub(&l[0], t[3], t[1])
 
 	fp2.square(t[7], &r[0])
 	fp2Sub(t[4], t[1], t[4])
@@ -106,23 +121,18 @@ func (e *Engine) double(f *fe12, r *PointG2, k int) {
 	fp2.mul(&r[2], t[1], t[6])
 	fp2Double(t[0], t[7])
 
-	fp2AddAssign(t[0], t[7])
-	fp2Neg(t[6], t[6])
-
-	// line eval
-	e.fp2.mul0Assign(t[6], &e.pairs[k].g1[1])
-	e.fp2.mul0Assign(t[0], &e.pairs[k].g1[0])
-	e.fp12.mul014(f, t[8], t[0], t[6])
-
+	fp2Add(&l[1], t[0], t[7])
+	fp2Neg(&l[2], t[6])
 }
 
-func (e *Engine) add(f *fe12, r *PointG2, k int) {
+// add adds affine point q to r and assigns coefficients of the line through r and q to l.
+func (e *Engine) add(l *line, r, q *PointG2) {
 	fp2, t := e.fp2, e.t2
 
-	fp2.mul(t[0], &e.pairs[k].g2[1], &r[2])
+	fp2.mul(t[0], &q[1], &r[2])
 	fp2Neg(t[0], t[0])
 	fp2AddAssign(t[0], &r[1])
-	fp2.mul(t[1], &e.pairs[k].g2[0], &r[2])
+	fp2.mul(t[1], &q[0], &r[2])
 	fp2Neg(t[1], t[1])
 	fp2AddAssign(t[1], &r[0])
 	fp2.square(t[2], t[0])
@@ -139,103 +149,79 @@ func (e *Engine) add(f *fe12, r *PointG2, k int) {
 	fp2.mul(t[2], &r[1], t[4])
 	fp2Sub(&r[1], t[3], t[2])
 	fp2.mulAssign(&r[2], t[4])
-	fp2.mul(t[2], t[1], &e.pairs[k].g2[1])
-	fp2.mul(t[3], t[0], &e.pairs[k].g2[0])
+	fp2.mul(t[2], t[1], &q[1])
+	fp2.mul(t[3], t[0], &q[0])
 
-	fp2SubAssign(t[3], t[2])
-	fp2Neg(t[0], t[0])
-
-	// line eval
-	e.fp2.mul0Assign(t[1], &e.pairs[k].g1[1])
-	e.fp2.mul0Assign(t[0], &e.pairs[k].g1[0])
-	e.fp12.mul014(f, t[3], t[0], t[1])
+	fp2Sub(&l[0], t[3], t[2])
+	fp2Neg(&l[1], t[0])
+	l[2].set(t[1])
 }
 
-func (e *Engine) nDoubleAdd(f *fe12, r []PointG2, n int) {
-	for i := 0; i < n; i++ {
-		e.fp12.squareAssign(f)
-		for j := 0; j < len(e.pairs); j++ {
-			e.double(f, &r[j], j)
-		}
-	}
-	for j := 0; j < len(e.pairs); j++ {
-		e.add(f, &r[j], j)
-	}
+// eval evaluates line at G1 point p in affine form and multiplies f by the result.
+func (e *Engine) eval(f *fe12, l *line, p *PointG1) {
+	t := e.t2
+	e.fp2.mul0(t[0], &l[1], &p[0])
+	e.fp2.mul0(t[1], &l[2], &p[1])
+	e.fp12.mul014(f, &l[0], t[0], t[1])
 }
 
-func (e *Engine) nDouble(f *fe12, r []PointG2, n int) {
-	for i := 0; i < n; i++ {
-		e.fp12.squareAssign(f)
-		for j := 0; j < len(e.pairs); j++ {
-			e.double(f, &r[j], j)
-		}
-	}
-}
+// millerLoopSteps is the number of line functions in Miller loop,
+// which are doublings for each bit of x after the leading one and additions for each other set bit.
+const millerLoopSteps = 68
+
+// millerLoopDoubles is x given as runs of doublings where each run but the last is followed by an addition.
+var millerLoopDoubles = [...]int{1, 2, 3, 9, 32, 16}
 
 func (e *Engine) millerLoop(f *fe12) {
 	f.one()
 
 	r := make([]PointG2, len(e.pairs))
 	for i := 0; i < len(e.pairs); i++ {
-		r[i].Set(e.pairs[i].g2)
+		if e.pairs[i].prepared == nil {
+			r[i].Set(e.pairs[i].g2)
+		}
 	}
 
-	for j := 0; j < len(e.pairs); j++ {
-		e.double(f, &r[j], j)
-	}
-	for j := 0; j < len(e.pairs); j++ {
-		e.add(f, &r[j], j)
+	l, step := new(line), 0
+	for i, n := range millerLoopDoubles {
+		for j := 0; j < n; j++ {
+			if step != 0 {
+				e.fp12.squareAssign(f)
+			}
+			e.lines(f, l, r, step, true)
+			step++
+		}
+		if i != len(millerLoopDoubles)-1 {
+			e.lines(f, l, r, step, false)
+			step++
+		}
 	}
 
-	e.nDoubleAdd(f, r, 2)
-	e.nDoubleAdd(f, r, 3)
-	e.nDoubleAdd(f, r, 9)
-	e.nDoubleAdd(f, r, 32)
-	e.nDouble(f, r, 16)
-
 	fp12Conjugate(f, f)
 }
 
-// exp raises element by x = -15132376222941642752
-func (e *Engine) exp(c, a *fe12) {
-	c.set(a)
-	e.fp12.cyclotomicSquare(c) // (a ^ 2)
-
-	// (a ^ (2 + 1)) ^ (2 ^ 2) = a ^ 12
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-
-	// (a ^ (12 + 1)) ^ (2 ^ 3) = a ^ 104
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-
-	// (a ^ (104 + 1)) ^ (2 ^ 9) = a ^ 53760
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	// (a ^ (53760 + 1)) ^ (2 ^ 32) = a ^ 230901736800256
-	e.fp12.mulAssign(c, a)
-	for i := 0; i < 32; i++ {
-		e.fp12.cyclotomicSquare(c)
+// lines multiplies f by lines of all pairs at given step of Miller loop.
+// Lines of prepared points are taken from their tables and other lines are calculated with doubling
+// or addition of points in r.
+func (e *Engine) lines(f *fe12, l *line, r []PointG2, step int, double bool) {
+	for j := 0; j < len(e.pairs); j++ {
+		p := &e.pairs[j]
+		switch {
+		case p.prepared != nil:
+			e.eval(f, &p.prepared.lines[step], p.g1)
+			continue
+		case double:
+			e.double(l, &r[j])
+		default:
+			e.add(l, &r[j], p.g2)
+		}
+		e.eval(f, l, p.g1)
 	}
+}
 
-	// (a ^ (230901736800256 + 1)) ^ (2 ^ 16) = a ^ 15132376222941642752
-	e.fp12.mulAssign(c, a)
-	for i := 0; i < 16; i++ {
-		e.fp12.cyclotomicSquare(c)
-	}
-	// invert chain result since x is negative
-	fp12Conjugate(c, c)
+// exp raises element by x = -15132376222941642752
+func (e *Engine) exp(c, a *fe12) {
+	e.fp12.cyclotomicExpX(c, a)
 }
 
 // expDrop raises element by x = -15132376222941642752 / 2
@@ -338,6 +324,34 @@ func (e *Engine) Result() *E {
 	return r
 }
 
+// CheckEquals computes pairing and checks if result is equal to given target group element.
+// Inverse of target is its conjugate and it is folded into the result so that the check is
+// whether the product is one, which holds if and only if the result is equal to target.
+// An error is returned if target is nil.
+func (e *Engine) CheckEquals(target *E) (bool, error) {
+	if target == nil {
+		return false, errors.New("target element must not be nil")
+	}
+	f := e.calculate()
+	t := new(fe12)
+	fp12Conjugate(t, target)
+	e.fp12.mulAssign(f, t)
+	return f.isOne(), nil
+}
+
+// ResultWithExponents adds pairs raised to given exponents as e(g1s[i], g2s[i])^exponents[i]
+// and returns pairing result of all pairs. Exponents are applied by scaling G1 points,
+// which is cheaper than exponentiation in target group.
+func (e *Engine) ResultWithExponents(g1s []*PointG1, g2s []*PointG2, exponents []*Fr) (*E, error) {
+	if len(g1s) != len(g2s) || len(g1s) != len(exponents) {
+		return nil, errors.New("point and exponent vectors should be in same length")
+	}
+	for i := range g1s {
+		e.AddPair(e.G1.MulScalar(e.G1.New(), g1s[i], exponents[i]), g2s[i])
+	}
+	return e.Result(), nil
+}
+
 // GT returns target group instance.
 func (e *Engine) GT() *GT {
 	return NewGT()
@@ -1,6 +1,7 @@
 package bls12381
 
 import (
+	"crypto/rand"
 	"math/big"
 	"testing"
 )
@@ -217,6 +218,63 @@ func TestPairingEmpty(t *testing.T) {
 	}
 }
 
+func TestPairingCheckEquals(t *testing.T) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	a, _ := new(Fr).Rand(rand.Reader)
+	b, _ := new(Fr).Rand(rand.Reader)
+	P1 := g1.MulScalar(g1.New(), g1.One(), a)
+	P2 := g2.MulScalar(g2.New(), g2.One(), b)
+	// e(a * G1, b * G2) == e(G1, G2) ^ (a * b)
+	target := bls.AddPair(g1.One(), g2.One()).Result()
+	gt.Exp(target, target, new(big.Int).Mul(a.ToBig(), b.ToBig()))
+	if ok, err := bls.AddPair(P1, P2).CheckEquals(target); err != nil || !ok {
+		t.Fatal("pairing should be equal to target")
+	}
+	bls.Reset()
+	if ok, err := bls.AddPair(P1, g2.One()).CheckEquals(target); err != nil || ok {
+		t.Fatal("pairing should not be equal to target")
+	}
+	bls.Reset()
+	if ok, err := bls.CheckEquals(gt.New().One()); err != nil || !ok {
+		t.Fatal("empty pairing should be equal to one")
+	}
+	if _, err := bls.CheckEquals(nil); err == nil {
+		t.Fatal("nil target should be rejected")
+	}
+}
+
+func TestPairingResultWithExponents(t *testing.T) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	n := 4
+	g1s, g2s, exponents := make([]*PointG1, n), make([]*PointG2, n), make([]*Fr, n)
+	expected := gt.New().One()
+	for i := 0; i < n; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, _ := new(Fr).Rand(rand.Reader)
+		exponents[i], _ = new(Fr).Rand(rand.Reader)
+		g1s[i] = g1.MulScalar(g1.New(), g1.One(), a)
+		g2s[i] = g2.MulScalar(g2.New(), g2.One(), b)
+		r := bls.AddPair(g1s[i], g2s[i]).Result()
+		gt.Exp(r, r, exponents[i].ToBig())
+		gt.Mul(expected, expected, r)
+	}
+	// already added pairs are included
+	gt.Mul(expected, expected, bls.AddPair(g1.One(), g2.One()).Result())
+	bls.AddPair(g1.One(), g2.One())
+	r, err := bls.ResultWithExponents(g1s, g2s, exponents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Equal(expected) {
+		t.Fatal("pairing with exponents failed")
+	}
+	if _, err := bls.ResultWithExponents(g1s, g2s, exponents[1:]); err == nil {
+		t.Fatal("vectors in different lengths must be rejected")
+	}
+}
+
 func BenchmarkPairing(t *testing.B) {
 	bls := NewEngine()
 	g1, g2, gt := bls.G1, bls.G2, bls.GT()
@@ -0,0 +1,234 @@
+package bls12381
+
+import "math/big"
+
+// Polynomials over base field and quadratic extension are represented with coefficients
+// in ascending order. They are used to find preimages of isogeny maps
+// where only roots of small degree polynomials are required.
+
+// polyRoot returns a root of f in base field.
+// Roots are found with Cantor-Zassenhaus method, that is, f is first reduced to
+// the product of its linear factors with gcd(f, X^p - X) and then split with
+// gcd(f, (X + d)^((p - 1) / 2) - 1) for d = 0, 1, ... until a linear factor is found.
+func polyRoot(f []fe) (*fe, bool) {
+	f = polyMonic(f)
+	if len(f) < 2 {
+		return nil, false
+	}
+	g := polyGCD(f, polySub(polyExpX(zero, pBig, f), []fe{{}, *new(fe).one()}))
+	for d := new(fe); len(g) > 2; add(d, d, one) {
+		t := polyExpX(d, pMinus1Over2, g)
+		h := polyGCD(g, polySub(t, []fe{*new(fe).one()}))
+		if len(h) > 1 && len(h) < len(g) {
+			g = h
+		}
+	}
+	if len(g) != 2 {
+		return nil, false
+	}
+	root := new(fe)
+	neg(root, &g[0])
+	return root, true
+}
+
+// polyExpX returns (X + d)^e mod m where m is monic.
+func polyExpX(d *fe, e *big.Int, m []fe) []fe {
+	base := polyMod([]fe{*d, *new(fe).one()}, m)
+	z := []fe{*new(fe).one()}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		z = polyMod(polyMul(z, z), m)
+		if e.Bit(i) == 1 {
+			z = polyMod(polyMul(z, base), m)
+		}
+	}
+	return z
+}
+
+func polyTrim(a []fe) []fe {
+	for len(a) > 0 && a[len(a)-1].isZero() {
+		a = a[:len(a)-1]
+	}
+	return a
+}
+
+func polyMonic(a []fe) []fe {
+	a = polyTrim(a)
+	if len(a) == 0 {
+		return a
+	}
+	c := make([]fe, len(a))
+	t := new(fe)
+	inverse(t, &a[len(a)-1])
+	for i := range a {
+		mul(&c[i], &a[i], t)
+	}
+	return c
+}
+
+func polySub(a, b []fe) []fe {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	c := make([]fe, n)
+	for i := range c {
+		if i < len(a) {
+			c[i].set(&a[i])
+		}
+		if i < len(b) {
+			sub(&c[i], &c[i], &b[i])
+		}
+	}
+	return polyTrim(c)
+}
+
+func polyMul(a, b []fe) []fe {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	c := make([]fe, len(a)+len(b)-1)
+	t := new(fe)
+	for i := range a {
+		for j := range b {
+			mul(t, &a[i], &b[j])
+			add(&c[i+j], &c[i+j], t)
+		}
+	}
+	return c
+}
+
+// polyMod returns a mod m where m is monic.
+func polyMod(a, m []fe) []fe {
+	n := len(m) - 1
+	r := make([]fe, len(a))
+	copy(r, a)
+	t := new(fe)
+	for i := len(r) - 1; i >= n; i-- {
+		for j := 0; j < n; j++ {
+			mul(t, &r[i], &m[j])
+			sub(&r[i-n+j], &r[i-n+j], t)
+		}
+		r[i].zero()
+	}
+	return polyTrim(r)
+}
+
+// polyGCD returns monic greatest common divisor of a and b.
+func polyGCD(a, b []fe) []fe {
+	a, b = polyMonic(a), polyMonic(b)
+	for len(b) > 0 {
+		a, b = b, polyMonic(polyMod(a, b))
+	}
+	return a
+}
+
+// poly2Root returns a root of f in quadratic extension field.
+// It follows polyRoot where the order of the field is p^2.
+func (e *fp2) poly2Root(f []fe2) (*fe2, bool) {
+	f = e.poly2Monic(f)
+	if len(f) < 2 {
+		return nil, false
+	}
+	g := e.poly2GCD(f, poly2Sub(e.poly2ExpX(new(fe2), pSquare, f), []fe2{{}, *new(fe2).one()}))
+	for d := new(fe2); len(g) > 2; fp2Add(d, d, e.one()) {
+		t := e.poly2ExpX(d, pSquareMinus1Over2, g)
+		h := e.poly2GCD(g, poly2Sub(t, []fe2{*new(fe2).one()}))
+		if len(h) > 1 && len(h) < len(g) {
+			g = h
+		}
+	}
+	if len(g) != 2 {
+		return nil, false
+	}
+	root := new(fe2)
+	fp2Neg(root, &g[0])
+	return root, true
+}
+
+func (e *fp2) poly2ExpX(d *fe2, exp *big.Int, m []fe2) []fe2 {
+	base := e.poly2Mod([]fe2{*d, *new(fe2).one()}, m)
+	z := []fe2{*new(fe2).one()}
+	for i := exp.BitLen() - 1; i >= 0; i-- {
+		z = e.poly2Mod(e.poly2Mul(z, z), m)
+		if exp.Bit(i) == 1 {
+			z = e.poly2Mod(e.poly2Mul(z, base), m)
+		}
+	}
+	return z
+}
+
+func poly2Trim(a []fe2) []fe2 {
+	for len(a) > 0 && a[len(a)-1].isZero() {
+		a = a[:len(a)-1]
+	}
+	return a
+}
+
+func (e *fp2) poly2Monic(a []fe2) []fe2 {
+	a = poly2Trim(a)
+	if len(a) == 0 {
+		return a
+	}
+	c := make([]fe2, len(a))
+	t := new(fe2)
+	e.inverse(t, &a[len(a)-1])
+	for i := range a {
+		e.mul(&c[i], &a[i], t)
+	}
+	return c
+}
+
+func poly2Sub(a, b []fe2) []fe2 {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	c := make([]fe2, n)
+	for i := range c {
+		if i < len(a) {
+			c[i].set(&a[i])
+		}
+		if i < len(b) {
+			fp2Sub(&c[i], &c[i], &b[i])
+		}
+	}
+	return poly2Trim(c)
+}
+
+func (e *fp2) poly2Mul(a, b []fe2) []fe2 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	c := make([]fe2, len(a)+len(b)-1)
+	t := new(fe2)
+	for i := range a {
+		for j := range b {
+			e.mul(t, &a[i], &b[j])
+			fp2Add(&c[i+j], &c[i+j], t)
+		}
+	}
+	return c
+}
+
+func (e *fp2) poly2Mod(a, m []fe2) []fe2 {
+	n := len(m) - 1
+	r := make([]fe2, len(a))
+	copy(r, a)
+	t := new(fe2)
+	for i := len(r) - 1; i >= n; i-- {
+		for j := 0; j < n; j++ {
+			e.mul(t, &r[i], &m[j])
+			fp2Sub(&r[i-n+j], &r[i-n+j], t)
+		}
+		r[i].zero()
+	}
+	return poly2Trim(r)
+}
+
+func (e *fp2) poly2GCD(a, b []fe2) []fe2 {
+	a, b = e.poly2Monic(a), e.poly2Monic(b)
+	for len(b) > 0 {
+		a, b = b, e.poly2Monic(e.poly2Mod(a, b))
+	}
+	return a
+}
@@ -0,0 +1,358 @@
+package bls12381
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// G1, G2, GT and Engine instances hold temporary values so they must not be shared between goroutines.
+// Functions here take instances from pools and return new values without modifying their inputs,
+// so they are safe for concurrent use. Pooled instances can also be borrowed with WithG1, WithG2,
+// WithGT and WithEngine for operations which are not covered by the functions.
+
+var g1Pool = sync.Pool{New: func() interface{} { return NewG1() }}
+
+var g2Pool = sync.Pool{New: func() interface{} { return NewG2() }}
+
+var gtPool = sync.Pool{New: func() interface{} { return NewGT() }}
+
+var enginePool = sync.Pool{New: func() interface{} { return NewEngine() }}
+
+// WithG1 calls f with a G1 instance taken from a pool and puts it back after f returns.
+// The instance must not be used after f returns.
+func WithG1(f func(g *G1)) {
+	g := g1Pool.Get().(*G1)
+	defer g1Pool.Put(g)
+	f(g)
+}
+
+// G1Add returns sum of two G1 points.
+func G1Add(a, b *PointG1) (r *PointG1) {
+	WithG1(func(g *G1) { r = g.Add(g.New(), a, b) })
+	return
+}
+
+// G1Sub returns difference of two G1 points.
+func G1Sub(a, b *PointG1) (r *PointG1) {
+	WithG1(func(g *G1) { r = g.Sub(g.New(), a, b) })
+	return
+}
+
+// G1Double returns double of a G1 point.
+func G1Double(p *PointG1) (r *PointG1) {
+	WithG1(func(g *G1) { r = g.Double(g.New(), p) })
+	return
+}
+
+// G1Neg returns negation of a G1 point.
+func G1Neg(p *PointG1) (r *PointG1) {
+	WithG1(func(g *G1) { r = g.Neg(g.New(), p) })
+	return
+}
+
+// G1Equal checks if two G1 points are equal.
+func G1Equal(a, b *PointG1) (ok bool) {
+	WithG1(func(g *G1) { ok = g.Equal(a, b) })
+	return
+}
+
+// G1IsOnCurve checks if a G1 point is on curve.
+func G1IsOnCurve(p *PointG1) (ok bool) {
+	WithG1(func(g *G1) { ok = g.IsOnCurve(p) })
+	return
+}
+
+// G1InCorrectSubgroup checks if a G1 point is in the correct subgroup.
+func G1InCorrectSubgroup(p *PointG1) (ok bool) {
+	WithG1(func(g *G1) { ok = g.InCorrectSubgroup(p) })
+	return
+}
+
+// G1ToAffine returns a G1 point in affine coordinates.
+func G1ToAffine(p *PointG1) (r *G1Affine) {
+	WithG1(func(g *G1) { r = g.ToAffine(new(G1Affine), p) })
+	return
+}
+
+// G1MulScalar returns multiplication of a G1 point by given scalar.
+func G1MulScalar(p *PointG1, e *Fr) (r *PointG1) {
+	WithG1(func(g *G1) { r = g.MulScalar(g.New(), p, e) })
+	return
+}
+
+// G1MulScalarCT returns multiplication of a G1 point by given secret scalar in constant time.
+func G1MulScalarCT(p *PointG1, e *Fr) (r *PointG1) {
+	WithG1(func(g *G1) { r = g.MulScalarCT(g.New(), p, e) })
+	return
+}
+
+// G1MulBase returns multiplication of the G1 generator by given scalar.
+func G1MulBase(e *Fr) (r *PointG1) {
+	WithG1(func(g *G1) { r = g.MulBase(g.New(), e) })
+	return
+}
+
+// G1MulBaseCT returns multiplication of the G1 generator by given secret scalar in constant time.
+func G1MulBaseCT(e *Fr) (r *PointG1) {
+	WithG1(func(g *G1) { r = g.MulBaseCT(g.New(), e) })
+	return
+}
+
+// G1MultiExp returns multi exponentiation of G1 points with given scalars.
+func G1MultiExp(points []*PointG1, scalars []*Fr) (r *PointG1, err error) {
+	WithG1(func(g *G1) { r, err = g.MultiExp(g.New(), points, scalars) })
+	return
+}
+
+// G1ToCompressed returns compressed encoding of a G1 point.
+func G1ToCompressed(p *PointG1) (out []byte) {
+	WithG1(func(g *G1) { out = g.ToCompressed(p) })
+	return
+}
+
+// G1FromCompressed decodes a G1 point in compressed form as G1.FromCompressed does.
+func G1FromCompressed(in []byte) (r *PointG1, err error) {
+	WithG1(func(g *G1) { r, err = g.FromCompressed(in) })
+	return
+}
+
+// G1ToUncompressed returns uncompressed encoding of a G1 point.
+func G1ToUncompressed(p *PointG1) (out []byte) {
+	WithG1(func(g *G1) { out = g.ToUncompressed(p) })
+	return
+}
+
+// G1FromUncompressed decodes a G1 point in uncompressed form as G1.FromUncompressed does.
+func G1FromUncompressed(in []byte) (r *PointG1, err error) {
+	WithG1(func(g *G1) { r, err = g.FromUncompressed(in) })
+	return
+}
+
+// G1HashToCurve hashes a message to a G1 point as G1.HashToCurve does.
+func G1HashToCurve(msg, domain []byte) (r *PointG1, err error) {
+	WithG1(func(g *G1) { r, err = g.HashToCurve(msg, domain) })
+	return
+}
+
+// G1EncodeToCurve encodes a message to a G1 point as G1.EncodeToCurve does.
+func G1EncodeToCurve(msg, domain []byte) (r *PointG1, err error) {
+	WithG1(func(g *G1) { r, err = g.EncodeToCurve(msg, domain) })
+	return
+}
+
+// WithG2 calls f with a G2 instance taken from a pool and puts it back after f returns.
+// The instance must not be used after f returns.
+func WithG2(f func(g *G2)) {
+	g := g2Pool.Get().(*G2)
+	defer g2Pool.Put(g)
+	f(g)
+}
+
+// G2Add returns sum of two G2 points.
+func G2Add(a, b *PointG2) (r *PointG2) {
+	WithG2(func(g *G2) { r = g.Add(g.New(), a, b) })
+	return
+}
+
+// G2Sub returns difference of two G2 points.
+func G2Sub(a, b *PointG2) (r *PointG2) {
+	WithG2(func(g *G2) { r = g.Sub(g.New(), a, b) })
+	return
+}
+
+// G2Double returns double of a G2 point.
+func G2Double(p *PointG2) (r *PointG2) {
+	WithG2(func(g *G2) { r = g.Double(g.New(), p) })
+	return
+}
+
+// G2Neg returns negation of a G2 point.
+func G2Neg(p *PointG2) (r *PointG2) {
+	WithG2(func(g *G2) { r = g.Neg(g.New(), p) })
+	return
+}
+
+// G2Equal checks if two G2 points are equal.
+func G2Equal(a, b *PointG2) (ok bool) {
+	WithG2(func(g *G2) { ok = g.Equal(a, b) })
+	return
+}
+
+// G2IsOnCurve checks if a G2 point is on curve.
+func G2IsOnCurve(p *PointG2) (ok bool) {
+	WithG2(func(g *G2) { ok = g.IsOnCurve(p) })
+	return
+}
+
+// G2InCorrectSubgroup checks if a G2 point is in the correct subgroup.
+func G2InCorrectSubgroup(p *PointG2) (ok bool) {
+	WithG2(func(g *G2) { ok = g.InCorrectSubgroup(p) })
+	return
+}
+
+// G2ToAffine returns a G2 point in affine coordinates.
+func G2ToAffine(p *PointG2) (r *G2Affine) {
+	WithG2(func(g *G2) { r = g.ToAffine(new(G2Affine), p) })
+	return
+}
+
+// G2MulScalar returns multiplication of a G2 point by given scalar.
+func G2MulScalar(p *PointG2, e *Fr) (r *PointG2) {
+	WithG2(func(g *G2) { r = g.MulScalar(g.New(), p, e) })
+	return
+}
+
+// G2MulScalarCT returns multiplication of a G2 point by given secret scalar in constant time.
+func G2MulScalarCT(p *PointG2, e *Fr) (r *PointG2) {
+	WithG2(func(g *G2) { r = g.MulScalarCT(g.New(), p, e) })
+	return
+}
+
+// G2MulBase returns multiplication of the G2 generator by given scalar.
+func G2MulBase(e *Fr) (r *PointG2) {
+	WithG2(func(g *G2) { r = g.MulBase(g.New(), e) })
+	return
+}
+
+// G2MulBaseCT returns multiplication of the G2 generator by given secret scalar in constant time.
+func G2MulBaseCT(e *Fr) (r *PointG2) {
+	WithG2(func(g *G2) { r = g.MulBaseCT(g.New(), e) })
+	return
+}
+
+// G2MultiExp returns multi exponentiation of G2 points with given scalars.
+func G2MultiExp(points []*PointG2, scalars []*Fr) (r *PointG2, err error) {
+	WithG2(func(g *G2) { r, err = g.MultiExp(g.New(), points, scalars) })
+	return
+}
+
+// G2ToCompressed returns compressed encoding of a G2 point.
+func G2ToCompressed(p *PointG2) (out []byte) {
+	WithG2(func(g *G2) { out = g.ToCompressed(p) })
+	return
+}
+
+// G2FromCompressed decodes a G2 point in compressed form as G2.FromCompressed does.
+func G2FromCompressed(in []byte) (r *PointG2, err error) {
+	WithG2(func(g *G2) { r, err = g.FromCompressed(in) })
+	return
+}
+
+// G2ToUncompressed returns uncompressed encoding of a G2 point.
+func G2ToUncompressed(p *PointG2) (out []byte) {
+	WithG2(func(g *G2) { out = g.ToUncompressed(p) })
+	return
+}
+
+// G2FromUncompressed decodes a G2 point in uncompressed form as G2.FromUncompressed does.
+func G2FromUncompressed(in []byte) (r *PointG2, err error) {
+	WithG2(func(g *G2) { r, err = g.FromUncompressed(in) })
+	return
+}
+
+// G2HashToCurve hashes a message to a G2 point as G2.HashToCurve does.
+func G2HashToCurve(msg, domain []byte) (r *PointG2, err error) {
+	WithG2(func(g *G2) { r, err = g.HashToCurve(msg, domain) })
+	return
+}
+
+// G2EncodeToCurve encodes a message to a G2 point as G2.EncodeToCurve does.
+func G2EncodeToCurve(msg, domain []byte) (r *PointG2, err error) {
+	WithG2(func(g *G2) { r, err = g.EncodeToCurve(msg, domain) })
+	return
+}
+
+// WithGT calls f with a GT instance taken from a pool and puts it back after f returns.
+// The instance must not be used after f returns.
+func WithGT(f func(g *GT)) {
+	g := gtPool.Get().(*GT)
+	defer gtPool.Put(g)
+	f(g)
+}
+
+// GTMul returns product of two target group elements.
+func GTMul(a, b *E) (r *E) {
+	WithGT(func(g *GT) {
+		r = g.New()
+		g.Mul(r, a, b)
+	})
+	return
+}
+
+// GTExp returns a target group element raised to given exponent.
+func GTExp(a *E, s *big.Int) (r *E) {
+	WithGT(func(g *GT) {
+		r = g.New()
+		g.Exp(r, a, s)
+	})
+	return
+}
+
+// GTExpFr returns a target group element raised to given scalar.
+func GTExpFr(a *E, s *Fr) (r *E) {
+	WithGT(func(g *GT) {
+		r = g.New()
+		g.ExpFr(r, a, s)
+	})
+	return
+}
+
+// GTMultiExp calculates multi exponentiation of target group elements as GT.MultiExp does.
+func GTMultiExp(bases []*E, scalars []*Fr) (r *E, err error) {
+	WithGT(func(g *GT) { r, err = g.MultiExp(g.New(), bases, scalars) })
+	return
+}
+
+// GTInverse returns inverse of a target group element.
+func GTInverse(a *E) (r *E) {
+	WithGT(func(g *GT) {
+		r = g.New()
+		g.Inverse(r, a)
+	})
+	return
+}
+
+// GTIsValid checks if an element is in target group.
+func GTIsValid(a *E) (ok bool) {
+	WithGT(func(g *GT) { ok = g.IsValid(a) })
+	return
+}
+
+// WithEngine calls f with a pairing engine taken from a pool and puts it back after f returns.
+// The engine has no pairs when f is called and must not be used after f returns.
+func WithEngine(f func(e *Engine)) {
+	e := enginePool.Get().(*Engine)
+	defer func() {
+		e.Reset()
+		enginePool.Put(e)
+	}()
+	f(e)
+}
+
+// Pair returns pairing of a G1 point and a G2 point.
+func Pair(g1 *PointG1, g2 *PointG2) (r *E) {
+	WithEngine(func(e *Engine) { r = e.AddPair(g1, g2).Result() })
+	return
+}
+
+// PairingCheck checks if product of pairings of given pairs of G1 and G2 points is equal to one.
+// Number of G1 points is expected to be equal to number of G2 points, otherwise an error is returned.
+func PairingCheck(g1s []*PointG1, g2s []*PointG2) (ok bool, err error) {
+	if len(g1s) != len(g2s) {
+		return false, errors.New("number of G1 and G2 points should be equal")
+	}
+	WithEngine(func(e *Engine) {
+		for i := range g1s {
+			e.AddPair(g1s[i], g2s[i])
+		}
+		ok = e.Check()
+	})
+	return ok, nil
+}
+
+// PrepareG2 computes Miller loop line coefficients of a G2 point as Engine.PrepareG2 does.
+func PrepareG2(p *PointG2) (r *PreparedG2) {
+	WithEngine(func(e *Engine) { r = e.PrepareG2(p) })
+	return
+}
@@ -0,0 +1,170 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// runGoroutines runs f in many goroutines concurrently and reports errors returned by f.
+// Tests here are meant to be run with the race detector.
+func runGoroutines(t *testing.T, f func() error) {
+	n := 16
+	errs := make(chan error, n)
+	wg := new(sync.WaitGroup)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- f()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestConcurrentG1(t *testing.T) {
+	// shared inputs are only read
+	p := NewG1().rand()
+	q := NewG1().randCorrect()
+	domain := []byte("BLS12381G1_XMD:SHA-256_SSWU_RO_TEST")
+	runGoroutines(t, func() error {
+		g := NewG1()
+		e, _ := new(Fr).Rand(rand.Reader)
+		if !G1Equal(G1Add(p, q), g.Add(g.New(), p, q)) ||
+			!G1Equal(G1Sub(p, q), g.Sub(g.New(), p, q)) ||
+			!G1Equal(G1Double(p), g.Double(g.New(), p)) ||
+			!G1Equal(G1Neg(p), g.Neg(g.New(), p)) {
+			return errors.New("arithmetic failed")
+		}
+		expected := g.mulScalar(g.New(), q, e)
+		if !G1Equal(G1MulScalar(q, e), expected) || !G1Equal(G1MulScalarCT(q, e), expected) {
+			return errors.New("scalar multiplication failed")
+		}
+		expected = g.mulScalar(g.New(), g.One(), e)
+		if !G1Equal(G1MulBase(e), expected) || !G1Equal(G1MulBaseCT(e), expected) {
+			return errors.New("generator multiplication failed")
+		}
+		r, err := G1MultiExp([]*PointG1{q, p}, []*Fr{e, e})
+		if err != nil || !G1Equal(r, g.Add(g.New(), g.mulScalar(g.New(), q, e), g.mulScalar(g.New(), p, e))) {
+			return errors.New("multi exponentiation failed")
+		}
+		if !G1IsOnCurve(p) || !G1InCorrectSubgroup(q) {
+			return errors.New("validation failed")
+		}
+		if d, err := G1FromCompressed(G1ToCompressed(q)); err != nil || !G1Equal(d, q) {
+			return errors.New("compressed encoding failed")
+		}
+		if d, err := G1FromUncompressed(G1ToUncompressed(q)); err != nil || !G1Equal(d, q) {
+			return errors.New("uncompressed encoding failed")
+		}
+		if !G1Equal(g.FromAffine(g.New(), G1ToAffine(p)), p) {
+			return errors.New("affine conversion failed")
+		}
+		h0, err := G1HashToCurve([]byte("msg"), domain)
+		if err != nil {
+			return err
+		}
+		h1, _ := g.HashToCurve([]byte("msg"), domain)
+		if !G1Equal(h0, h1) {
+			return errors.New("hash to curve failed")
+		}
+		return nil
+	})
+}
+
+func TestConcurrentG2(t *testing.T) {
+	p := NewG2().rand()
+	q := NewG2().randCorrect()
+	domain := []byte("BLS12381G2_XMD:SHA-256_SSWU_RO_TEST")
+	runGoroutines(t, func() error {
+		g := NewG2()
+		e, _ := new(Fr).Rand(rand.Reader)
+		if !G2Equal(G2Add(p, q), g.Add(g.New(), p, q)) ||
+			!G2Equal(G2Sub(p, q), g.Sub(g.New(), p, q)) ||
+			!G2Equal(G2Double(p), g.Double(g.New(), p)) ||
+			!G2Equal(G2Neg(p), g.Neg(g.New(), p)) {
+			return errors.New("arithmetic failed")
+		}
+		expected := g.mulScalar(g.New(), q, e)
+		if !G2Equal(G2MulScalar(q, e), expected) || !G2Equal(G2MulScalarCT(q, e), expected) {
+			return errors.New("scalar multiplication failed")
+		}
+		expected = g.mulScalar(g.New(), g.One(), e)
+		if !G2Equal(G2MulBase(e), expected) || !G2Equal(G2MulBaseCT(e), expected) {
+			return errors.New("generator multiplication failed")
+		}
+		r, err := G2MultiExp([]*PointG2{q, p}, []*Fr{e, e})
+		if err != nil || !G2Equal(r, g.Add(g.New(), g.mulScalar(g.New(), q, e), g.mulScalar(g.New(), p, e))) {
+			return errors.New("multi exponentiation failed")
+		}
+		if !G2IsOnCurve(p) || !G2InCorrectSubgroup(q) {
+			return errors.New("validation failed")
+		}
+		if d, err := G2FromCompressed(G2ToCompressed(q)); err != nil || !G2Equal(d, q) {
+			return errors.New("compressed encoding failed")
+		}
+		if d, err := G2FromUncompressed(G2ToUncompressed(q)); err != nil || !G2Equal(d, q) {
+			return errors.New("uncompressed encoding failed")
+		}
+		if !G2Equal(g.FromAffine(g.New(), G2ToAffine(p)), p) {
+			return errors.New("affine conversion failed")
+		}
+		h0, err := G2HashToCurve([]byte("msg"), domain)
+		if err != nil {
+			return err
+		}
+		h1, _ := g.HashToCurve([]byte("msg"), domain)
+		if !G2Equal(h0, h1) {
+			return errors.New("hash to curve failed")
+		}
+		return nil
+	})
+}
+
+func TestConcurrentPairing(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	p1, p2 := g1.One(), g2.One()
+	expected := NewEngine().AddPair(p1, p2).Result()
+	runGoroutines(t, func() error {
+		g1, g2 := NewG1(), NewG2()
+		e, _ := new(Fr).Rand(rand.Reader)
+		if !Pair(p1, p2).Equal(expected) {
+			return errors.New("pairing failed")
+		}
+		ok, err := PairingCheck(
+			[]*PointG1{g1.MulScalar(g1.New(), p1, e), g1.Neg(g1.New(), p1)},
+			[]*PointG2{p2, g2.MulScalar(g2.New(), p2, e)},
+		)
+		if err != nil || !ok {
+			return errors.New("pairing check failed")
+		}
+		gt := NewGT()
+		s := e.ToBig()
+		exp := gt.New()
+		gt.Exp(exp, expected, s)
+		if !GTExp(expected, s).Equal(exp) || !GTIsValid(exp) {
+			return errors.New("target group exponentiation failed")
+		}
+		if m, err := GTMultiExp([]*E{expected}, []*Fr{e}); err != nil || !m.Equal(exp) || !GTExpFr(expected, e).Equal(exp) {
+			return errors.New("target group multi exponentiation failed")
+		}
+		if !GTMul(exp, GTInverse(exp)).IsOne() {
+			return errors.New("target group inversion failed")
+		}
+		if !bytes.Equal(gt.ToBytes(Pair(g1.MulScalar(g1.New(), p1, e), p2)), gt.ToBytes(exp)) {
+			return errors.New("bilinearity failed")
+		}
+		return nil
+	})
+	if _, err := PairingCheck([]*PointG1{p1}, nil); err == nil {
+		t.Fatal("length mismatch must be rejected")
+	}
+}
@@ -0,0 +1,107 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Line functions of Miller loop depend only on the G2 point, so for a G2 point which is used
+// in many pairings they are computed once and only evaluated at G1 points afterwards.
+//
+// Prepared points are encoded as a byte which is one for point at infinity and zero otherwise,
+// followed by the coefficients of the lines for a point which is not at infinity. Decoding checks
+// that coefficients are canonical field elements but not that they belong to a G2 point,
+// so encoded prepared points are expected to be kept in trusted storage.
+
+const preparedG2Size = 1 + millerLoopSteps*3*2*fpByteSize
+
+var errPreparedG2Encoding = errors.New("bad prepared G2 point encoding")
+
+// PreparedG2 is a G2 point with precomputed Miller loop line coefficients.
+// A prepared point is not modified after it is built so it can be used concurrently.
+type PreparedG2 struct {
+	infinity bool
+	lines    [millerLoopSteps]line
+}
+
+// PrepareG2 computes Miller loop line coefficients of a G2 point.
+func (e *Engine) PrepareG2(p *PointG2) *PreparedG2 {
+	prepared := new(PreparedG2)
+	if e.G2.IsZero(p) {
+		prepared.infinity = true
+		return prepared
+	}
+	q := e.G2.affine(new(PointG2), p)
+	r := new(PointG2).Set(q)
+	step := 0
+	for i, n := range millerLoopDoubles {
+		for j := 0; j < n; j++ {
+			e.double(&prepared.lines[step], r)
+			step++
+		}
+		if i != len(millerLoopDoubles)-1 {
+			e.add(&prepared.lines[step], r, q)
+			step++
+		}
+	}
+	return prepared
+}
+
+// AddPairPrepared adds a G1 point and a prepared G2 point pair to pairing engine.
+func (e *Engine) AddPairPrepared(g1 *PointG1, g2 *PreparedG2) *Engine {
+	if !(e.G1.IsZero(g1) || g2.infinity) {
+		e.pairs = append(e.pairs, pair{g1: e.G1.affine(new(PointG1), g1), prepared: g2})
+	}
+	return e
+}
+
+// AddPairPreparedInv adds a G1 point and a prepared G2 point pair to pairing engine. G1 point is negated.
+func (e *Engine) AddPairPreparedInv(g1 *PointG1, g2 *PreparedG2) *Engine {
+	return e.AddPairPrepared(e.G1.Neg(e.G1.New(), g1), g2)
+}
+
+// IsZero checks if the prepared point is point at infinity.
+func (p *PreparedG2) IsZero() bool {
+	return p.infinity
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (p *PreparedG2) MarshalBinary() ([]byte, error) {
+	if p.infinity {
+		return []byte{1}, nil
+	}
+	fp2 := newFp2()
+	out := make([]byte, 1, preparedG2Size)
+	for i := range p.lines {
+		for j := range p.lines[i] {
+			out = append(out, fp2.toBytes(&p.lines[i][j])...)
+		}
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (p *PreparedG2) UnmarshalBinary(data []byte) error {
+	if len(data) == 1 && data[0] == 1 {
+		*p = PreparedG2{infinity: true}
+		return nil
+	}
+	if len(data) != preparedG2Size || data[0] != 0 {
+		return errPreparedG2Encoding
+	}
+	fp2 := newFp2()
+	r := new(PreparedG2)
+	data = data[1:]
+	for i := range r.lines {
+		for j := range r.lines[i] {
+			c, err := fp2.fromBytes(data[:2*fpByteSize])
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrNonCanonical, err)
+			}
+			r.lines[i][j].set(c)
+			data = data[2*fpByteSize:]
+		}
+	}
+	*p = *r
+	return nil
+}
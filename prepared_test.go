@@ -0,0 +1,89 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestPreparedG2(t *testing.T) {
+	e := NewEngine()
+	g1, g2 := e.G1, e.G2
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, _ := new(Fr).Rand(rand.Reader)
+		p1 := g1.MulScalar(g1.New(), g1.One(), a)
+		p2 := g2.MulScalar(g2.New(), g2.One(), b)
+		expected := e.AddPair(p1, p2).Result()
+		prepared := e.PrepareG2(p2)
+		if !e.AddPairPrepared(p1, prepared).Result().Equal(expected) {
+			t.Fatal("pairing with prepared point failed")
+		}
+		// e(a * G1, b * G2) * e(-b * G1, a * G2) * e(G1, 0) = 1
+		q1 := g1.MulScalar(g1.New(), g1.One(), b)
+		q2 := g2.MulScalar(g2.New(), g2.One(), a)
+		e.AddPairPrepared(p1, prepared)
+		e.AddPairInv(q1, q2)
+		e.AddPairPrepared(g1.One(), e.PrepareG2(g2.Zero()))
+		if !e.Check() {
+			t.Fatal("pairing check with prepared and regular points failed")
+		}
+		e.Reset()
+		e.AddPairPreparedInv(q1, e.PrepareG2(q2))
+		e.AddPair(p1, p2)
+		if !e.Check() {
+			t.Fatal("pairing check with negated prepared pair failed")
+		}
+		e.Reset()
+	}
+}
+
+func TestPreparedG2Serialization(t *testing.T) {
+	e := NewEngine()
+	p1, p2 := e.G1.randCorrect(), e.G2.randCorrect()
+	prepared := e.PrepareG2(p2)
+	data, err := prepared.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != preparedG2Size {
+		t.Fatal("bad encoding length")
+	}
+	decoded := new(PreparedG2)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !e.AddPairPrepared(p1, decoded).Result().Equal(e.AddPair(p1, p2).Result()) {
+		t.Fatal("decoded prepared point gives bad result")
+	}
+	data, _ = e.PrepareG2(e.G2.Zero()).MarshalBinary()
+	if err := decoded.UnmarshalBinary(data); err != nil || !decoded.IsZero() {
+		t.Fatal("point at infinity is not decoded")
+	}
+	data, _ = prepared.MarshalBinary()
+	if err := decoded.UnmarshalBinary(data[1:]); err != errPreparedG2Encoding {
+		t.Fatal("bad length must be rejected")
+	}
+	for i := 1; i < 1+fpByteSize; i++ {
+		data[i] = 0xff
+	}
+	if err := decoded.UnmarshalBinary(data); !errors.Is(err, ErrNonCanonical) {
+		t.Fatal("non canonical coefficient must be rejected")
+	}
+}
+
+func BenchmarkPairingPrepared(t *testing.B) {
+	e := NewEngine()
+	p1, p2 := e.G1.randCorrect(), e.G2.randCorrect()
+	prepared := e.PrepareG2(p2)
+	t.Run("Regular", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			e.AddPair(p1, p2).Result()
+		}
+	})
+	t.Run("Prepared", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			e.AddPairPrepared(p1, prepared).Result()
+		}
+	})
+}
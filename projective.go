@@ -0,0 +1,384 @@
+package bls12381
+
+// Points in homogeneous projective coordinates (X, Y, Z) represent affine point (X/Z, Y/Z)
+// and point at infinity is (0, Y, 0). Addition and doubling use complete formulas of
+// Renes, Costello and Batina for curves with a = 0 (https://eprint.iacr.org/2015/1060, algorithms 7, 8 and 9).
+// Formulas are valid for all inputs including zero, equal and opposite points,
+// so they have no branches and run in constant time.
+
+// PointG1Proj is type for point in G1 in homogeneous projective coordinates.
+type PointG1Proj [3]fe
+
+// Set copies values of one point to another.
+func (p *PointG1Proj) Set(p2 *PointG1Proj) *PointG1Proj {
+	p[0].set(&p2[0])
+	p[1].set(&p2[1])
+	p[2].set(&p2[2])
+	return p
+}
+
+// Zero returns G1 point in projective coordinates in point at infinity representation.
+func (p *PointG1Proj) Zero() *PointG1Proj {
+	p[0].zero()
+	p[1].one()
+	p[2].zero()
+	return p
+}
+
+func (p *PointG1Proj) cmov(q *PointG1Proj, cond uint64) *PointG1Proj {
+	p[0].cmov(&q[0], cond)
+	p[1].cmov(&q[1], cond)
+	p[2].cmov(&q[2], cond)
+	return p
+}
+
+// mulBy3b multiplies by 3 * b = 12.
+func mulBy3b(c, a *fe) {
+	var t fe
+	double(&t, a)
+	addAssign(&t, a)
+	double(c, &t)
+	doubleAssign(c)
+}
+
+// ToProjective converts a G1 point in Jacobian coordinates into projective coordinates
+// and assigns the result to point at first argument.
+func (g *G1) ToProjective(r *PointG1Proj, p *PointG1) *PointG1Proj {
+	t := g.t
+	// (X, Y, Z) -> (X * Z, Y, Z^3)
+	zero := ctIsZero(&p[2])
+	square(t[0], &p[2])
+	mul(t[0], t[0], &p[2])
+	mul(&r[0], &p[0], &p[2])
+	r[1].set(&p[1])
+	r[2].set(t[0])
+	r.cmov(new(PointG1Proj).Zero(), zero)
+	return r
+}
+
+// FromProjective converts a G1 point in projective coordinates into Jacobian coordinates
+// and assigns the result to point at first argument.
+func (g *G1) FromProjective(r *PointG1, p *PointG1Proj) *PointG1 {
+	t := g.t
+	// (X, Y, Z) -> (X * Z, Y * Z^2, Z)
+	zero := ctIsZero(&p[2])
+	square(t[0], &p[2])
+	mul(&r[1], &p[1], t[0])
+	mul(&r[0], &p[0], &p[2])
+	r[2].set(&p[2])
+	r.cmov(g.Zero(), zero)
+	return r
+}
+
+// AddProjective adds two G1 points in projective coordinates and assigns the result to point at first argument.
+func (g *G1) AddProjective(r, p1, p2 *PointG1Proj) *PointG1Proj {
+	t := g.t
+	t0, t1, t2, t3, t4 := t[0], t[1], t[2], t[3], t[4]
+	x3, y3, z3 := t[5], t[6], t[7]
+	mul(t0, &p1[0], &p2[0])
+	mul(t1, &p1[1], &p2[1])
+	mul(t2, &p1[2], &p2[2])
+	add(t3, &p1[0], &p1[1])
+	add(t4, &p2[0], &p2[1])
+	mul(t3, t3, t4)
+	add(t4, t0, t1)
+	subAssign(t3, t4)
+	add(t4, &p1[1], &p1[2])
+	add(x3, &p2[1], &p2[2])
+	mul(t4, t4, x3)
+	add(x3, t1, t2)
+	subAssign(t4, x3)
+	add(x3, &p1[0], &p1[2])
+	add(y3, &p2[0], &p2[2])
+	mul(x3, x3, y3)
+	add(y3, t0, t2)
+	sub(y3, x3, y3)
+	double(x3, t0)
+	addAssign(t0, x3)
+	mulBy3b(t2, t2)
+	add(z3, t1, t2)
+	subAssign(t1, t2)
+	mulBy3b(y3, y3)
+	mul(x3, t4, y3)
+	mul(t2, t3, t1)
+	sub(x3, t2, x3)
+	mul(y3, y3, t0)
+	mul(t1, t1, z3)
+	addAssign(y3, t1)
+	mul(t0, t0, t3)
+	mul(z3, z3, t4)
+	addAssign(z3, t0)
+	r[0].set(x3)
+	r[1].set(y3)
+	r[2].set(z3)
+	return r
+}
+
+// AddMixedProjective adds a G1 point in projective coordinates and a G1 point p2 in affine form
+// and assigns the result to point at first argument. p2 is allowed to be zero.
+func (g *G1) AddMixedProjective(r, p1 *PointG1Proj, p2 *PointG1) *PointG1Proj {
+	t := g.t
+	t0, t1, t2, t3, t4 := t[0], t[1], t[2], t[3], t[4]
+	x3, y3, z3 := t[5], t[6], t[7]
+	zero := ctIsZero(&p2[2])
+	mul(t0, &p1[0], &p2[0])
+	mul(t1, &p1[1], &p2[1])
+	add(t3, &p2[0], &p2[1])
+	add(t4, &p1[0], &p1[1])
+	mul(t3, t3, t4)
+	add(t4, t0, t1)
+	subAssign(t3, t4)
+	mul(t4, &p2[1], &p1[2])
+	addAssign(t4, &p1[1])
+	mul(y3, &p2[0], &p1[2])
+	addAssign(y3, &p1[0])
+	double(x3, t0)
+	addAssign(t0, x3)
+	mulBy3b(t2, &p1[2])
+	add(z3, t1, t2)
+	subAssign(t1, t2)
+	mulBy3b(y3, y3)
+	mul(x3, t4, y3)
+	mul(t2, t3, t1)
+	sub(x3, t2, x3)
+	mul(y3, y3, t0)
+	mul(t1, t1, z3)
+	addAssign(y3, t1)
+	mul(t0, t0, t3)
+	mul(z3, z3, t4)
+	addAssign(z3, t0)
+	x3.cmov(&p1[0], zero)
+	y3.cmov(&p1[1], zero)
+	z3.cmov(&p1[2], zero)
+	r[0].set(x3)
+	r[1].set(y3)
+	r[2].set(z3)
+	return r
+}
+
+// DoubleProjective doubles a G1 point in projective coordinates and assigns the result to point at first argument.
+func (g *G1) DoubleProjective(r, p *PointG1Proj) *PointG1Proj {
+	t := g.t
+	t0, t1, t2 := t[0], t[1], t[2]
+	x3, y3, z3 := t[5], t[6], t[7]
+	square(t0, &p[1])
+	double(z3, t0)
+	doubleAssign(z3)
+	doubleAssign(z3)
+	mul(t1, &p[1], &p[2])
+	square(t2, &p[2])
+	mulBy3b(t2, t2)
+	mul(x3, t2, z3)
+	add(y3, t0, t2)
+	mul(z3, z3, t1)
+	double(t1, t2)
+	addAssign(t2, t1)
+	subAssign(t0, t2)
+	mul(y3, y3, t0)
+	addAssign(y3, x3)
+	mul(t1, &p[0], &p[1])
+	mul(x3, t0, t1)
+	doubleAssign(x3)
+	r[0].set(x3)
+	r[1].set(y3)
+	r[2].set(z3)
+	return r
+}
+
+// NegProjective negates a G1 point in projective coordinates and assigns the result to point at first argument.
+func (g *G1) NegProjective(r, p *PointG1Proj) *PointG1Proj {
+	r[0].set(&p[0])
+	ctNeg(&r[1], &p[1])
+	r[2].set(&p[2])
+	return r
+}
+
+// PointG2Proj is type for point in G2 in homogeneous projective coordinates.
+type PointG2Proj [3]fe2
+
+// Set copies values of one point to another.
+func (p *PointG2Proj) Set(p2 *PointG2Proj) *PointG2Proj {
+	p[0].set(&p2[0])
+	p[1].set(&p2[1])
+	p[2].set(&p2[2])
+	return p
+}
+
+// Zero returns G2 point in projective coordinates in point at infinity representation.
+func (p *PointG2Proj) Zero() *PointG2Proj {
+	p[0].zero()
+	p[1].one()
+	p[2].zero()
+	return p
+}
+
+func (p *PointG2Proj) cmov(q *PointG2Proj, cond uint64) *PointG2Proj {
+	p[0].cmov(&q[0], cond)
+	p[1].cmov(&q[1], cond)
+	p[2].cmov(&q[2], cond)
+	return p
+}
+
+// mulBy3b2 multiplies by 3 * b = 12 * (1 + u).
+func mulBy3b2(c, a *fe2) {
+	var t fe
+	// c0 = 12a0 - 12a1
+	// c1 = 12a0 + 12a1
+	sub(&t, &a[0], &a[1])
+	add(&c[1], &a[0], &a[1])
+	c[0].set(&t)
+	mulBy3b(&c[0], &c[0])
+	mulBy3b(&c[1], &c[1])
+}
+
+// ToProjective converts a G2 point in Jacobian coordinates into projective coordinates
+// and assigns the result to point at first argument.
+func (g *G2) ToProjective(r *PointG2Proj, p *PointG2) *PointG2Proj {
+	t := g.t
+	// (X, Y, Z) -> (X * Z, Y, Z^3)
+	zero := ctIsZero2(&p[2])
+	g.f.square(t[0], &p[2])
+	g.f.mul(t[0], t[0], &p[2])
+	g.f.mul(&r[0], &p[0], &p[2])
+	r[1].set(&p[1])
+	r[2].set(t[0])
+	r.cmov(new(PointG2Proj).Zero(), zero)
+	return r
+}
+
+// FromProjective converts a G2 point in projective coordinates into Jacobian coordinates
+// and assigns the result to point at first argument.
+func (g *G2) FromProjective(r *PointG2, p *PointG2Proj) *PointG2 {
+	t := g.t
+	// (X, Y, Z) -> (X * Z, Y * Z^2, Z)
+	zero := ctIsZero2(&p[2])
+	g.f.square(t[0], &p[2])
+	g.f.mul(&r[1], &p[1], t[0])
+	g.f.mul(&r[0], &p[0], &p[2])
+	r[2].set(&p[2])
+	r.cmov(g.Zero(), zero)
+	return r
+}
+
+// AddProjective adds two G2 points in projective coordinates and assigns the result to point at first argument.
+func (g *G2) AddProjective(r, p1, p2 *PointG2Proj) *PointG2Proj {
+	t := g.t
+	t0, t1, t2, t3, t4 := t[0], t[1], t[2], t[3], t[4]
+	x3, y3, z3 := t[5], t[6], t[7]
+	g.f.mul(t0, &p1[0], &p2[0])
+	g.f.mul(t1, &p1[1], &p2[1])
+	g.f.mul(t2, &p1[2], &p2[2])
+	fp2Add(t3, &p1[0], &p1[1])
+	fp2Add(t4, &p2[0], &p2[1])
+	g.f.mul(t3, t3, t4)
+	fp2Add(t4, t0, t1)
+	fp2SubAssign(t3, t4)
+	fp2Add(t4, &p1[1], &p1[2])
+	fp2Add(x3, &p2[1], &p2[2])
+	g.f.mul(t4, t4, x3)
+	fp2Add(x3, t1, t2)
+	fp2SubAssign(t4, x3)
+	fp2Add(x3, &p1[0], &p1[2])
+	fp2Add(y3, &p2[0], &p2[2])
+	g.f.mul(x3, x3, y3)
+	fp2Add(y3, t0, t2)
+	fp2Sub(y3, x3, y3)
+	fp2Double(x3, t0)
+	fp2AddAssign(t0, x3)
+	mulBy3b2(t2, t2)
+	fp2Add(z3, t1, t2)
+	fp2SubAssign(t1, t2)
+	mulBy3b2(y3, y3)
+	g.f.mul(x3, t4, y3)
+	g.f.mul(t2, t3, t1)
+	fp2Sub(x3, t2, x3)
+	g.f.mul(y3, y3, t0)
+	g.f.mul(t1, t1, z3)
+	fp2AddAssign(y3, t1)
+	g.f.mul(t0, t0, t3)
+	g.f.mul(z3, z3, t4)
+	fp2AddAssign(z3, t0)
+	r[0].set(x3)
+	r[1].set(y3)
+	r[2].set(z3)
+	return r
+}
+
+// AddMixedProjective adds a G2 point in projective coordinates and a G2 point p2 in affine form
+// and assigns the result to point at first argument. p2 is allowed to be zero.
+func (g *G2) AddMixedProjective(r, p1 *PointG2Proj, p2 *PointG2) *PointG2Proj {
+	t := g.t
+	t0, t1, t2, t3, t4 := t[0], t[1], t[2], t[3], t[4]
+	x3, y3, z3 := t[5], t[6], t[7]
+	zero := ctIsZero2(&p2[2])
+	g.f.mul(t0, &p1[0], &p2[0])
+	g.f.mul(t1, &p1[1], &p2[1])
+	fp2Add(t3, &p2[0], &p2[1])
+	fp2Add(t4, &p1[0], &p1[1])
+	g.f.mul(t3, t3, t4)
+	fp2Add(t4, t0, t1)
+	fp2SubAssign(t3, t4)
+	g.f.mul(t4, &p2[1], &p1[2])
+	fp2AddAssign(t4, &p1[1])
+	g.f.mul(y3, &p2[0], &p1[2])
+	fp2AddAssign(y3, &p1[0])
+	fp2Double(x3, t0)
+	fp2AddAssign(t0, x3)
+	mulBy3b2(t2, &p1[2])
+	fp2Add(z3, t1, t2)
+	fp2SubAssign(t1, t2)
+	mulBy3b2(y3, y3)
+	g.f.mul(x3, t4, y3)
+	g.f.mul(t2, t3, t1)
+	fp2Sub(x3, t2, x3)
+	g.f.mul(y3, y3, t0)
+	g.f.mul(t1, t1, z3)
+	fp2AddAssign(y3, t1)
+	g.f.mul(t0, t0, t3)
+	g.f.mul(z3, z3, t4)
+	fp2AddAssign(z3, t0)
+	x3.cmov(&p1[0], zero)
+	y3.cmov(&p1[1], zero)
+	z3.cmov(&p1[2], zero)
+	r[0].set(x3)
+	r[1].set(y3)
+	r[2].set(z3)
+	return r
+}
+
+// DoubleProjective doubles a G2 point in projective coordinates and assigns the result to point at first argument.
+func (g *G2) DoubleProjective(r, p *PointG2Proj) *PointG2Proj {
+	t := g.t
+	t0, t1, t2 := t[0], t[1], t[2]
+	x3, y3, z3 := t[5], t[6], t[7]
+	g.f.square(t0, &p[1])
+	fp2Double(z3, t0)
+	fp2DoubleAssign(z3)
+	fp2DoubleAssign(z3)
+	g.f.mul(t1, &p[1], &p[2])
+	g.f.square(t2, &p[2])
+	mulBy3b2(t2, t2)
+	g.f.mul(x3, t2, z3)
+	fp2Add(y3, t0, t2)
+	g.f.mul(z3, z3, t1)
+	fp2Double(t1, t2)
+	fp2AddAssign(t2, t1)
+	fp2SubAssign(t0, t2)
+	g.f.mul(y3, y3, t0)
+	fp2AddAssign(y3, x3)
+	g.f.mul(t1, &p[0], &p[1])
+	g.f.mul(x3, t0, t1)
+	fp2DoubleAssign(x3)
+	r[0].set(x3)
+	r[1].set(y3)
+	r[2].set(z3)
+	return r
+}
+
+// NegProjective negates a G2 point in projective coordinates and assigns the result to point at first argument.
+func (g *G2) NegProjective(r, p *PointG2Proj) *PointG2Proj {
+	r[0].set(&p[0])
+	fp2NegCT(&r[1], &p[1])
+	r[2].set(&p[2])
+	return r
+}
@@ -0,0 +1,77 @@
+package bls12381
+
+import "testing"
+
+func TestG1Projective(t *testing.T) {
+	g := NewG1()
+	for i := 0; i < fuz; i++ {
+		p, q := g.rand(), g.randAffine()
+		for _, a := range []*PointG1{p, g.Zero(), new(PointG1).Set(q), g.Neg(g.New(), q)} {
+			for _, b := range []*PointG1{q, g.Zero()} {
+				ap, bp := new(PointG1Proj), new(PointG1Proj)
+				g.ToProjective(ap, a)
+				g.ToProjective(bp, b)
+				expected, r, rp := g.New(), g.New(), new(PointG1Proj)
+				if !g.Equal(a, g.FromProjective(r, ap)) {
+					t.Fatal("conversion failed")
+				}
+				g.Add(expected, a, b)
+				g.AddProjective(rp, ap, bp)
+				if !g.Equal(expected, g.FromProjective(r, rp)) {
+					t.Fatal("projective addition failed")
+				}
+				g.AddMixedProjective(rp, ap, b)
+				if !g.Equal(expected, g.FromProjective(r, rp)) {
+					t.Fatal("projective mixed addition failed")
+				}
+				g.Double(expected, a)
+				g.DoubleProjective(rp, ap)
+				if !g.Equal(expected, g.FromProjective(r, rp)) {
+					t.Fatal("projective doubling failed")
+				}
+				g.Neg(expected, a)
+				g.NegProjective(rp, ap)
+				if !g.Equal(expected, g.FromProjective(r, rp)) {
+					t.Fatal("projective negation failed")
+				}
+			}
+		}
+	}
+}
+
+func TestG2Projective(t *testing.T) {
+	g := NewG2()
+	for i := 0; i < fuz; i++ {
+		p, q := g.rand(), g.randAffine()
+		for _, a := range []*PointG2{p, g.Zero(), new(PointG2).Set(q), g.Neg(g.New(), q)} {
+			for _, b := range []*PointG2{q, g.Zero()} {
+				ap, bp := new(PointG2Proj), new(PointG2Proj)
+				g.ToProjective(ap, a)
+				g.ToProjective(bp, b)
+				expected, r, rp := g.New(), g.New(), new(PointG2Proj)
+				if !g.Equal(a, g.FromProjective(r, ap)) {
+					t.Fatal("conversion failed")
+				}
+				g.Add(expected, a, b)
+				g.AddProjective(rp, ap, bp)
+				if !g.Equal(expected, g.FromProjective(r, rp)) {
+					t.Fatal("projective addition failed")
+				}
+				g.AddMixedProjective(rp, ap, b)
+				if !g.Equal(expected, g.FromProjective(r, rp)) {
+					t.Fatal("projective mixed addition failed")
+				}
+				g.Double(expected, a)
+				g.DoubleProjective(rp, ap)
+				if !g.Equal(expected, g.FromProjective(r, rp)) {
+					t.Fatal("projective doubling failed")
+				}
+				g.Neg(expected, a)
+				g.NegProjective(rp, ap)
+				if !g.Equal(expected, g.FromProjective(r, rp)) {
+					t.Fatal("projective negation failed")
+				}
+			}
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package bls12381
 
 // swuMapG1 is implementation of Simplified Shallue-van de Woestijne-Ulas Method
-// follows the implmentation at draft-irtf-cfrg-hash-to-curve-06.
+// follows the implmentation at RFC 9380.
 func swuMapG1(u *fe) (*fe, *fe) {
 	var params = swuParamsForG1
 	var tv [4]*fe
@@ -49,7 +49,7 @@ func swuMapG1(u *fe) (*fe, *fe) {
 }
 
 // swuMapG2 is implementation of Simplified Shallue-van de Woestijne-Ulas Method
-// defined at draft-irtf-cfrg-hash-to-curve-06.
+// defined at RFC 9380.
 func swuMapG2(e *fp2, u *fe2) (*fe2, *fe2) {
 	if e == nil {
 		e = newFp2()
@@ -98,6 +98,90 @@ func swuMapG2(e *fp2, u *fe2) (*fe2, *fe2) {
 	return x, y
 }
 
+// swuMapG1CT is constant time version of swuMapG1.
+// It applies a uniform sequence of one inversion and two square root exponentiations
+// and selects intermediate values with conditional moves.
+func swuMapG1CT(u *fe) (*fe, *fe) {
+	var params = swuParamsForG1
+	var tv [4]*fe
+	for i := 0; i < 4; i++ {
+		tv[i] = new(fe)
+	}
+	square(tv[0], u)
+	mul(tv[0], tv[0], params.z)
+	square(tv[1], tv[0])
+	x1 := new(fe)
+	add(x1, tv[0], tv[1])
+	inverseCT(x1, x1)
+	e1 := ctIsZero(x1)
+	one := new(fe).one()
+	add(x1, x1, one)
+	x1.cmov(params.zInv, e1)
+	mul(x1, x1, params.minusBOverA)
+	gx1 := new(fe)
+	square(gx1, x1)
+	add(gx1, gx1, params.a)
+	mul(gx1, gx1, x1)
+	add(gx1, gx1, params.b)
+	x2 := new(fe)
+	mul(x2, tv[0], x1)
+	mul(tv[1], tv[0], tv[1])
+	gx2 := new(fe)
+	mul(gx2, gx1, tv[1])
+	e2 := isQuadraticNonResidueCT(gx1) ^ 1
+	x, y2 := new(fe).set(x2), new(fe).set(gx2)
+	x.cmov(x1, e2)
+	y2.cmov(gx1, e2)
+	y := new(fe)
+	sqrtCT(y, y2)
+	ctNeg(tv[2], y)
+	y.cmov(tv[2], ctParity(y)^ctParity(u))
+	return x, y
+}
+
+// swuMapG2CT is constant time version of swuMapG2.
+// It applies a uniform sequence of one inversion and two square root exponentiations
+// and selects intermediate values with conditional moves.
+func swuMapG2CT(e *fp2, u *fe2) (*fe2, *fe2) {
+	if e == nil {
+		e = newFp2()
+	}
+	params := swuParamsForG2
+	var tv [4]*fe2
+	for i := 0; i < 4; i++ {
+		tv[i] = e.new()
+	}
+	e.square(tv[0], u)
+	e.mul(tv[0], tv[0], params.z)
+	e.square(tv[1], tv[0])
+	x1 := e.new()
+	fp2Add(x1, tv[0], tv[1])
+	e.inverseCT(x1, x1)
+	e1 := ctIsZero2(x1)
+	fp2Add(x1, x1, e.one())
+	x1.cmov(params.zInv, e1)
+	e.mul(x1, x1, params.minusBOverA)
+	gx1 := e.new()
+	e.square(gx1, x1)
+	fp2Add(gx1, gx1, params.a)
+	e.mul(gx1, gx1, x1)
+	fp2Add(gx1, gx1, params.b)
+	x2 := e.new()
+	e.mul(x2, tv[0], x1)
+	e.mul(tv[1], tv[0], tv[1])
+	gx2 := e.new()
+	e.mul(gx2, gx1, tv[1])
+	e2 := e.isQuadraticNonResidueCT(gx1) ^ 1
+	x, y2 := e.new().set(x2), e.new().set(gx2)
+	x.cmov(x1, e2)
+	y2.cmov(gx1, e2)
+	y := e.new()
+	e.sqrtCT(y, y2)
+	fp2NegCT(tv[2], y)
+	y.cmov(tv[2], ctParity2(y)^ctParity2(u))
+	return x, y
+}
+
 var swuParamsForG1 = struct {
 	z           *fe
 	zInv        *fe
@@ -140,3 +224,149 @@ var swuParamsForG2 = struct {
 		fe{0x29c2aaaaaab85af8, 0xbf133368e30eeefa, 0xc7a27a7206cffb45, 0x9dee04ce44c9425c, 0x04a15ce53464ce83, 0x0b8fcaf5b59dac95},
 	},
 }
+
+// swuInverseG1 returns all field elements u such that swuMapG1(u) = (x, y).
+// For a point on isogenous curve x is either x1 = c * (1 + 1 / (t^2 + t)) or x2 = t * x1
+// where t = z * u^2 and c = -B / A. Each case is a quadratic equation in t and
+// together with exceptional values t = 0 and t = -1 gives candidates for u
+// which are verified with the forward map. There are at most four preimages.
+func swuInverseG1(x, y *fe) []*fe {
+	params := swuParamsForG1
+	c, t0, t1, d := params.minusBOverA, new(fe), new(fe), new(fe)
+	ts := []*fe{new(fe), new(fe).set(negativeOne)}
+	// x1 case: t^2 + t - 1 / w = 0 where w = x / c - 1
+	w := new(fe)
+	inverse(w, c)
+	mul(w, w, x)
+	sub(w, w, one)
+	if !w.isZero() {
+		inverse(d, w)
+		double(d, d)
+		double(d, d)
+		add(d, d, one)
+		if sqrt(d, d) {
+			sub(t0, d, one)
+			mul(t0, t0, twoInv)
+			neg(t1, d)
+			sub(t1, t1, one)
+			mul(t1, t1, twoInv)
+			ts = append(ts, new(fe).set(t0), new(fe).set(t1))
+		}
+	}
+	// x2 case: c * t^2 + k * t + k = 0 where k = c - x
+	k := new(fe)
+	sub(k, c, x)
+	square(d, k)
+	mul(t0, c, k)
+	double(t0, t0)
+	double(t0, t0)
+	sub(d, d, t0)
+	if sqrt(d, d) {
+		inv := new(fe)
+		double(inv, c)
+		inverse(inv, inv)
+		sub(t0, d, k)
+		mul(t0, t0, inv)
+		neg(t1, d)
+		sub(t1, t1, k)
+		mul(t1, t1, inv)
+		ts = append(ts, new(fe).set(t0), new(fe).set(t1))
+	}
+	preimages := []*fe{}
+	for _, t := range ts {
+		// u^2 = t / z where zInv = -1 / z
+		u := new(fe)
+		mul(u, t, params.zInv)
+		neg(u, u)
+		if !sqrt(u, u) {
+			continue
+		}
+		uNeg := new(fe)
+		neg(uNeg, u)
+	candidates:
+		for _, u := range []*fe{u, uNeg} {
+			for _, v := range preimages {
+				if v.equal(u) {
+					continue candidates
+				}
+			}
+			x0, y0 := swuMapG1(u)
+			if x0.equal(x) && y0.equal(y) {
+				preimages = append(preimages, u)
+			}
+		}
+	}
+	return preimages
+}
+
+// swuInverseG2 returns all field elements u such that swuMapG2(u) = (x, y).
+// It follows swuInverseG1.
+func swuInverseG2(e *fp2, x, y *fe2) []*fe2 {
+	params := swuParamsForG2
+	c, t0, t1, d := params.minusBOverA, e.new(), e.new(), e.new()
+	one := e.one()
+	ts := []*fe2{e.new(), e.new().set(negativeOne2)}
+	// x1 case: t^2 + t - 1 / w = 0 where w = x / c - 1
+	w := e.new()
+	e.inverse(w, c)
+	e.mul(w, w, x)
+	fp2Sub(w, w, one)
+	if !w.isZero() {
+		e.inverse(d, w)
+		fp2Double(d, d)
+		fp2Double(d, d)
+		fp2Add(d, d, one)
+		if e.sqrt(d, d) {
+			fp2Sub(t0, d, one)
+			e.mul0(t0, t0, twoInv)
+			fp2Neg(t1, d)
+			fp2Sub(t1, t1, one)
+			e.mul0(t1, t1, twoInv)
+			ts = append(ts, e.new().set(t0), e.new().set(t1))
+		}
+	}
+	// x2 case: c * t^2 + k * t + k = 0 where k = c - x
+	k := e.new()
+	fp2Sub(k, c, x)
+	e.square(d, k)
+	e.mul(t0, c, k)
+	fp2Double(t0, t0)
+	fp2Double(t0, t0)
+	fp2Sub(d, d, t0)
+	if e.sqrt(d, d) {
+		inv := e.new()
+		fp2Double(inv, c)
+		e.inverse(inv, inv)
+		fp2Sub(t0, d, k)
+		e.mul(t0, t0, inv)
+		fp2Neg(t1, d)
+		fp2Sub(t1, t1, k)
+		e.mul(t1, t1, inv)
+		ts = append(ts, e.new().set(t0), e.new().set(t1))
+	}
+	preimages := []*fe2{}
+	for _, t := range ts {
+		// u^2 = t / z where zInv = -1 / z
+		u := e.new()
+		e.mul(u, t, params.zInv)
+		fp2Neg(u, u)
+		if !e.sqrt(u, u) {
+			continue
+		}
+		uNeg := e.new()
+		fp2Neg(uNeg, u)
+	candidates:
+		for _, u := range []*fe2{u, uNeg} {
+			for _, v := range preimages {
+				if v.equal(u) {
+					continue candidates
+				}
+			}
+			x0, y0 := swuMapG2(e, u)
+			if x0.equal(x) && y0.equal(y) {
+				preimages = append(preimages, u)
+			}
+		}
+	}
+	return preimages
+}